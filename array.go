@@ -6,6 +6,10 @@ package v8go
 
 /* #include "v8go.h" */
 import "C"
+import (
+	"runtime"
+	"unsafe"
+)
 
 // Array is a JavaScript Array object, a subtype of Object.
 type Array struct {
@@ -28,3 +32,135 @@ func (c *Context) NewArray(length int) *Array {
 func (a *Array) Length() uint32 {
 	return uint32(C.ArrayLength(a.valuePtr()))
 }
+
+// Push appends vals to the end of the array, the same way
+// Array.prototype.push does, and returns the array's new length.
+func (a *Array) Push(vals ...Valuer) (uint32, error) {
+	cArgs, argptr := convertArgs(vals)
+	rtn := C.ArrayPush(a.valuePtr(), C.int(len(vals)), argptr)
+	runtime.KeepAlive(cArgs)
+	return lengthResult(a.ctx, rtn)
+}
+
+// Pop removes and returns the last element of the array, the same way
+// Array.prototype.pop does. If the array is empty, it returns the JS
+// undefined value.
+func (a *Array) Pop() (*Value, error) {
+	rtn := C.ArrayPop(a.valuePtr())
+	return valueResult(a.ctx, rtn)
+}
+
+// Shift removes and returns the first element of the array, the same way
+// Array.prototype.shift does. If the array is empty, it returns the JS
+// undefined value.
+func (a *Array) Shift() (*Value, error) {
+	rtn := C.ArrayShift(a.valuePtr())
+	return valueResult(a.ctx, rtn)
+}
+
+// Unshift inserts vals at the start of the array, the same way
+// Array.prototype.unshift does, and returns the array's new length.
+func (a *Array) Unshift(vals ...Valuer) (uint32, error) {
+	cArgs, argptr := convertArgs(vals)
+	rtn := C.ArrayUnshift(a.valuePtr(), C.int(len(vals)), argptr)
+	runtime.KeepAlive(cArgs)
+	return lengthResult(a.ctx, rtn)
+}
+
+// Slice returns a new Array containing a shallow copy of the elements from
+// start up to (but not including) end, the same way Array.prototype.slice
+// does, including its support for negative offsets counting back from the
+// end of the array.
+func (a *Array) Slice(start, end int) (*Array, error) {
+	rtn := C.ArraySlice(a.valuePtr(), C.int(start), C.int(end))
+	return arrayResult(a.ctx, rtn)
+}
+
+// lengthResult is the shared implementation behind Push and Unshift: both
+// report the array's new length as a JS Number, wrapped in the same RtnValue
+// used by error-returning calls elsewhere.
+func lengthResult(ctx *Context, rtn C.RtnValue) (uint32, error) {
+	val, err := valueResult(ctx, rtn)
+	if err != nil {
+		return 0, err
+	}
+	return val.Uint32(), nil
+}
+
+func arrayResult(ctx *Context, rtn C.RtnValue) (*Array, error) {
+	val, err := valueResult(ctx, rtn)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		return nil, err
+	}
+	return &Array{Object{Value: obj.Value}}, nil
+}
+
+// arrayIteratorBatchSize is the number of elements Iterator fetches from V8
+// per underlying C call.
+const arrayIteratorBatchSize = 256
+
+// ArrayIterator walks an Array's elements in order, fetching them in
+// batches rather than making a separate C call (and Go/C transition) for
+// every index.
+type ArrayIterator struct {
+	arr   *Array
+	total uint32
+	next  uint32
+	batch []C.ValuePtr
+	pos   int
+	err   error
+}
+
+// Iterator returns an ArrayIterator over a's elements.
+func (a *Array) Iterator() *ArrayIterator {
+	return &ArrayIterator{arr: a, total: a.Length()}
+}
+
+// Next advances the iterator and reports whether a further (idx, val) pair
+// is available. Once Next returns false, call Err to check whether
+// iteration stopped because of an error rather than reaching the end.
+func (it *ArrayIterator) Next() (idx uint32, val *Value, ok bool) {
+	if it.err != nil {
+		return 0, nil, false
+	}
+	if it.pos >= len(it.batch) {
+		if it.next >= it.total {
+			return 0, nil, false
+		}
+		if err := it.fillBatch(); err != nil {
+			it.err = err
+			return 0, nil, false
+		}
+	}
+	idx = it.next
+	val = &Value{ptr: it.batch[it.pos], ctx: it.arr.ctx}
+	it.pos++
+	it.next++
+	return idx, val, true
+}
+
+// Err returns the first error encountered while iterating, if any.
+func (it *ArrayIterator) Err() error {
+	return it.err
+}
+
+func (it *ArrayIterator) fillBatch() error {
+	n := it.total - it.next
+	if n > arrayIteratorBatchSize {
+		n = arrayIteratorBatchSize
+	}
+	rtn := C.ArrayGetRange(it.arr.valuePtr(), C.uint32_t(it.next), C.uint32_t(n))
+	if rtn.error.msg != nil {
+		return newJSError(rtn.error)
+	}
+	cVals := (*[1 << 20]C.ValuePtr)(unsafe.Pointer(rtn.values))[:rtn.count:rtn.count]
+	it.batch = make([]C.ValuePtr, rtn.count)
+	copy(it.batch, cVals)
+	C.free(unsafe.Pointer(rtn.values))
+	it.pos = 0
+	return nil
+}
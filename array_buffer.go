@@ -0,0 +1,264 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+/*
+#include <stdlib.h>
+#include "v8go.h"
+*/
+import "C"
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"runtime"
+	"unsafe"
+)
+
+// NewArrayBuffer creates a JS ArrayBuffer backed by a copy of data,
+// associated with iso's internal Context.
+func NewArrayBuffer(iso *Isolate, data []byte) (*Value, error) {
+	return iso.internalContext.NewArrayBuffer(data)
+}
+
+// NewArrayBuffer creates a JS ArrayBuffer backed by a copy of data. This
+// lets Go code hand large binary blobs (images, protobuf frames, wasm
+// modules) to JS without going through string/base64 encoding.
+func (c *Context) NewArrayBuffer(data []byte) (*Value, error) {
+	var dataPtr *C.char
+	if len(data) > 0 {
+		dataPtr = (*C.char)(unsafe.Pointer(&data[0]))
+	}
+	ptr := C.NewValueArrayBuffer(c.ptr, dataPtr, C.int(len(data)))
+	if ptr == nil {
+		return nil, errors.New("v8go: failed to create ArrayBuffer")
+	}
+	return &Value{ptr: ptr, ctx: c}, nil
+}
+
+// NewUint8Array creates a JS Uint8Array backed by a copy of data,
+// associated with iso's internal Context.
+func NewUint8Array(iso *Isolate, data []byte) (*Value, error) {
+	return iso.internalContext.NewUint8Array(data)
+}
+
+// NewUint8Array creates a JS Uint8Array backed by a copy of data.
+func (c *Context) NewUint8Array(data []byte) (*Value, error) {
+	var dataPtr *C.char
+	if len(data) > 0 {
+		dataPtr = (*C.char)(unsafe.Pointer(&data[0]))
+	}
+	ptr := C.NewValueUint8Array(c.ptr, dataPtr, C.int(len(data)))
+	if ptr == nil {
+		return nil, errors.New("v8go: failed to create Uint8Array")
+	}
+	return &Value{ptr: ptr, ctx: c}, nil
+}
+
+// NewFloat64Array creates a JS Float64Array backed by a copy of data,
+// associated with iso's internal Context.
+func NewFloat64Array(iso *Isolate, data []float64) (*Value, error) {
+	return iso.internalContext.NewFloat64Array(data)
+}
+
+// NewFloat64Array creates a JS Float64Array backed by a copy of data.
+func (c *Context) NewFloat64Array(data []float64) (*Value, error) {
+	buf := make([]byte, len(data)*8)
+	for i, f := range data {
+		binary.LittleEndian.PutUint64(buf[i*8:], math.Float64bits(f))
+	}
+	var dataPtr *C.char
+	if len(buf) > 0 {
+		dataPtr = (*C.char)(unsafe.Pointer(&buf[0]))
+	}
+	ptr := C.NewValueFloat64Array(c.ptr, dataPtr, C.int(len(data)))
+	if ptr == nil {
+		return nil, errors.New("v8go: failed to create Float64Array")
+	}
+	return &Value{ptr: ptr, ctx: c}, nil
+}
+
+// newTypedArrayFromBytes creates a JS TypedArray of the given kind backed by
+// a copy of raw, which must already be in that kind's native element
+// encoding (e.g. the bytes TypedArrayBytes returns for a value of that
+// kind). It exists to let UnmarshalCBOR reconstruct the non-Uint8Array,
+// non-Float64Array typed-array kinds, which otherwise have no Go-facing
+// constructor in this package.
+func (c *Context) newTypedArrayFromBytes(kind string, raw []byte) (*Value, error) {
+	var dataPtr *C.char
+	if len(raw) > 0 {
+		dataPtr = (*C.char)(unsafe.Pointer(&raw[0]))
+	}
+	var ptr C.ValuePtr
+	switch kind {
+	case "Uint8ClampedArray":
+		ptr = C.NewValueUint8ClampedArray(c.ptr, dataPtr, C.int(len(raw)))
+	case "Int8Array":
+		ptr = C.NewValueInt8Array(c.ptr, dataPtr, C.int(len(raw)))
+	case "Uint16Array":
+		ptr = C.NewValueUint16Array(c.ptr, dataPtr, C.int(len(raw)/2))
+	case "Int16Array":
+		ptr = C.NewValueInt16Array(c.ptr, dataPtr, C.int(len(raw)/2))
+	case "Uint32Array":
+		ptr = C.NewValueUint32Array(c.ptr, dataPtr, C.int(len(raw)/4))
+	case "Int32Array":
+		ptr = C.NewValueInt32Array(c.ptr, dataPtr, C.int(len(raw)/4))
+	case "BigUint64Array":
+		ptr = C.NewValueBigUint64Array(c.ptr, dataPtr, C.int(len(raw)/8))
+	case "BigInt64Array":
+		ptr = C.NewValueBigInt64Array(c.ptr, dataPtr, C.int(len(raw)/8))
+	case "Float32Array":
+		ptr = C.NewValueFloat32Array(c.ptr, dataPtr, C.int(len(raw)/4))
+	case "Float64Array":
+		ptr = C.NewValueFloat64Array(c.ptr, dataPtr, C.int(len(raw)/8))
+	default:
+		return nil, fmt.Errorf("v8go: unsupported typed array kind %q", kind)
+	}
+	if ptr == nil {
+		return nil, fmt.Errorf("v8go: failed to create %s", kind)
+	}
+	return &Value{ptr: ptr, ctx: c}, nil
+}
+
+// TypedArray is a JS TypedArray value (Uint8Array, Float64Array, and so on),
+// returned by the New*ArrayOfLength constructors and by Value.AsTypedArray.
+type TypedArray struct {
+	*Value
+}
+
+// AsTypedArray returns v as a *TypedArray, or an error if v is not one of
+// the TypedArray flavors (see Value.IsTypedArray).
+func (v *Value) AsTypedArray() (*TypedArray, error) {
+	if !v.IsTypedArray() {
+		return nil, errors.New("v8go: value is not a TypedArray")
+	}
+	return &TypedArray{v}, nil
+}
+
+// Bytes returns a zero-copy view onto the TypedArray's backing store, in its
+// own element encoding (e.g. 8 bytes per element for a Float64Array), so Go
+// code can hand binary data (images, protobuf frames) to JS or read it back
+// without base64-encoding through JSON. The returned slice has the same
+// lifetime constraints as ArrayBufferView's.
+func (t *TypedArray) Bytes() []byte {
+	view, err := t.Value.ArrayBufferView()
+	if err != nil {
+		// AsTypedArray already confirmed this value is a TypedArray, so
+		// ArrayBufferView can only fail if it has no backing store at all.
+		return nil
+	}
+	return view.Bytes()
+}
+
+// NewUint8ArrayOfLength creates a JS Uint8Array of length zero-initialized
+// bytes, associated with iso's internal Context.
+func NewUint8ArrayOfLength(iso *Isolate, length int) (*TypedArray, error) {
+	return iso.internalContext.NewUint8ArrayOfLength(length)
+}
+
+// NewUint8ArrayOfLength creates a JS Uint8Array of length zero-initialized
+// bytes.
+func (c *Context) NewUint8ArrayOfLength(length int) (*TypedArray, error) {
+	ptr := C.NewValueUint8ArrayOfLength(c.ptr, C.int(length))
+	if ptr == nil {
+		return nil, errors.New("v8go: failed to create Uint8Array")
+	}
+	return &TypedArray{&Value{ptr: ptr, ctx: c}}, nil
+}
+
+// NewFloat64ArrayOfLength creates a JS Float64Array of length
+// zero-initialized elements, associated with iso's internal Context.
+func NewFloat64ArrayOfLength(iso *Isolate, length int) (*TypedArray, error) {
+	return iso.internalContext.NewFloat64ArrayOfLength(length)
+}
+
+// NewFloat64ArrayOfLength creates a JS Float64Array of length
+// zero-initialized elements.
+func (c *Context) NewFloat64ArrayOfLength(length int) (*TypedArray, error) {
+	ptr := C.NewValueFloat64ArrayOfLength(c.ptr, C.int(length))
+	if ptr == nil {
+		return nil, errors.New("v8go: failed to create Float64Array")
+	}
+	return &TypedArray{&Value{ptr: ptr, ctx: c}}, nil
+}
+
+// ArrayBufferBytes returns a copy of the bytes backing this value, which
+// must be an ArrayBuffer (see IsArrayBuffer). Use TypedArrayBytes for any
+// of the TypedArray flavors, or ArrayBufferView for a zero-copy window onto
+// the same memory.
+func (v *Value) ArrayBufferBytes() ([]byte, error) {
+	if !v.IsArrayBuffer() {
+		return nil, errors.New("v8go: value is not an ArrayBuffer")
+	}
+	return v.copyBackingBytes()
+}
+
+// TypedArrayBytes returns a copy of the bytes backing this value, which
+// must be one of the TypedArray flavors (see IsTypedArray). The bytes are
+// in the typed array's own element encoding (e.g. 8 bytes per element for a
+// Float64Array), not necessarily plain octets.
+func (v *Value) TypedArrayBytes() ([]byte, error) {
+	if !v.IsTypedArray() {
+		return nil, errors.New("v8go: value is not a TypedArray")
+	}
+	return v.copyBackingBytes()
+}
+
+func (v *Value) copyBackingBytes() ([]byte, error) {
+	rtn := C.ValueToArrayBufferBytes(v.valuePtr())
+	if rtn.data == nil {
+		return nil, errors.New("v8go: value has no ArrayBuffer backing store")
+	}
+	defer C.free(unsafe.Pointer(rtn.data))
+	return C.GoBytes(unsafe.Pointer(rtn.data), C.int(rtn.length)), nil
+}
+
+// ArrayBufferView is a zero-copy window onto the backing store of an
+// ArrayBuffer or TypedArray value, returned by Value.ArrayBufferView.
+//
+// Unlike ArrayBufferBytes/TypedArrayBytes, Bytes aliases V8's own memory
+// instead of copying it, which is much cheaper for large buffers but comes
+// with a sharp lifetime constraint: the returned slice is only valid until
+// Release is called or the owning Context/Isolate is closed, whichever
+// comes first. Callers that need the data to outlive the view must copy it
+// out (e.g. with append([]byte(nil), view.Bytes()...)) before releasing.
+type ArrayBufferView struct {
+	bytes []byte
+	value *Value
+}
+
+// Bytes returns the aliased backing-store slice. See ArrayBufferView's doc
+// comment for its lifetime constraints.
+func (a *ArrayBufferView) Bytes() []byte {
+	return a.bytes
+}
+
+// Release drops the view's references to the backing-store slice and to its
+// Value; Bytes returns nil afterwards. A slice obtained from Bytes earlier
+// must not be used after calling Release.
+func (a *ArrayBufferView) Release() {
+	a.bytes = nil
+	a.value = nil
+}
+
+// ArrayBufferView returns a zero-copy view onto this value's backing store.
+// v must be an ArrayBuffer or TypedArray.
+func (v *Value) ArrayBufferView() (*ArrayBufferView, error) {
+	if !v.IsArrayBuffer() && !v.IsTypedArray() {
+		return nil, errors.New("v8go: value is not an ArrayBuffer or TypedArray")
+	}
+	rtn := C.ValueGetArrayBufferView(v.valuePtr())
+	if rtn.data == nil {
+		return nil, errors.New("v8go: value has no ArrayBuffer backing store")
+	}
+	// The slice aliases memory owned by the isolate; keep v alive for as
+	// long as the view might be read from.
+	runtime.KeepAlive(v)
+	return &ArrayBufferView{
+		bytes: (*[1 << 30]byte)(unsafe.Pointer(rtn.data))[:rtn.length:rtn.length],
+		value: v,
+	}, nil
+}
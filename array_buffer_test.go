@@ -0,0 +1,155 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"bytes"
+	"testing"
+
+	v8go "rogchap.com/v8go"
+)
+
+func TestNewArrayBuffer(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	data := []byte{1, 2, 3, 4}
+	val, err := ctx.NewArrayBuffer(data)
+	if err != nil {
+		t.Fatalf("NewArrayBuffer failed: %v", err)
+	}
+	if !val.IsArrayBuffer() {
+		t.Fatalf("expected an ArrayBuffer value, got %s", val)
+	}
+
+	got, err := val.ArrayBufferBytes()
+	if err != nil {
+		t.Fatalf("ArrayBufferBytes failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("expected %v, got %v", data, got)
+	}
+}
+
+func TestNewUint8Array(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	data := []byte("hello")
+	val, err := v8go.NewUint8Array(ctx.Isolate(), data)
+	if err != nil {
+		t.Fatalf("NewUint8Array failed: %v", err)
+	}
+	if !val.IsUint8Array() {
+		t.Fatalf("expected a Uint8Array value, got %s", val)
+	}
+
+	got, err := val.TypedArrayBytes()
+	if err != nil {
+		t.Fatalf("TypedArrayBytes failed: %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("expected %v, got %v", data, got)
+	}
+}
+
+func TestValueArrayBufferView(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	data := []byte{9, 8, 7, 6, 5}
+	val, err := ctx.NewArrayBuffer(data)
+	if err != nil {
+		t.Fatalf("NewArrayBuffer failed: %v", err)
+	}
+
+	view, err := val.ArrayBufferView()
+	if err != nil {
+		t.Fatalf("ArrayBufferView failed: %v", err)
+	}
+	defer view.Release()
+
+	if !bytes.Equal(view.Bytes(), data) {
+		t.Errorf("expected %v, got %v", data, view.Bytes())
+	}
+}
+
+func TestNewUint8ArrayOfLength(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	ta, err := ctx.NewUint8ArrayOfLength(4)
+	if err != nil {
+		t.Fatalf("NewUint8ArrayOfLength failed: %v", err)
+	}
+	if !ta.IsUint8Array() {
+		t.Fatalf("expected a Uint8Array value, got %s", ta)
+	}
+	b := ta.Bytes()
+	if len(b) != 4 {
+		t.Fatalf("expected 4 bytes, got %d", len(b))
+	}
+	for i, c := range b {
+		if c != 0 {
+			t.Errorf("expected byte %d to be zero-initialized, got %d", i, c)
+		}
+	}
+}
+
+func TestValueAsTypedArray(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := v8go.NewUint8Array(ctx.Isolate(), []byte("hello"))
+	if err != nil {
+		t.Fatalf("NewUint8Array failed: %v", err)
+	}
+	ta, err := val.AsTypedArray()
+	if err != nil {
+		t.Fatalf("AsTypedArray failed: %v", err)
+	}
+	if !bytes.Equal(ta.Bytes(), []byte("hello")) {
+		t.Errorf("expected %q, got %q", "hello", ta.Bytes())
+	}
+
+	notTyped, err := ctx.RunScript(`"not a typed array"`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := notTyped.AsTypedArray(); err == nil {
+		t.Error("expected an error for a non-TypedArray value, got <nil>")
+	}
+}
+
+func TestArrayBufferBytesWrongType(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`"not a buffer"`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := val.ArrayBufferBytes(); err == nil {
+		t.Error("expected an error for a non-ArrayBuffer value, got <nil>")
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8go "rogchap.com/v8go"
+)
+
+func runArrayScript(t *testing.T, ctx *v8go.Context, src string) *v8go.Array {
+	t.Helper()
+	val, err := ctx.RunScript(src, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	return &v8go.Array{Object: *obj}
+}
+
+func TestArrayPushPop(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	arr := runArrayScript(t, ctx, `[1, 2]`)
+
+	three, err := ctx.NewValue(int32(3))
+	if err != nil {
+		t.Fatalf("NewValue failed: %v", err)
+	}
+	length, err := arr.Push(three)
+	if err != nil {
+		t.Fatalf("Push failed: %v", err)
+	}
+	if length != 3 {
+		t.Errorf("expected length 3, got %d", length)
+	}
+
+	last, err := arr.Pop()
+	if err != nil {
+		t.Fatalf("Pop failed: %v", err)
+	}
+	if last.Integer() != 3 {
+		t.Errorf("expected 3, got %v", last)
+	}
+	if arr.Length() != 2 {
+		t.Errorf("expected length 2 after Pop, got %d", arr.Length())
+	}
+}
+
+func TestArrayShiftUnshift(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	arr := runArrayScript(t, ctx, `[2, 3]`)
+
+	one, err := ctx.NewValue(int32(1))
+	if err != nil {
+		t.Fatalf("NewValue failed: %v", err)
+	}
+	length, err := arr.Unshift(one)
+	if err != nil {
+		t.Fatalf("Unshift failed: %v", err)
+	}
+	if length != 3 {
+		t.Errorf("expected length 3, got %d", length)
+	}
+
+	first, err := arr.Shift()
+	if err != nil {
+		t.Fatalf("Shift failed: %v", err)
+	}
+	if first.Integer() != 1 {
+		t.Errorf("expected 1, got %v", first)
+	}
+	if arr.Length() != 2 {
+		t.Errorf("expected length 2 after Shift, got %d", arr.Length())
+	}
+}
+
+func TestArraySlice(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	arr := runArrayScript(t, ctx, `[1, 2, 3, 4, 5]`)
+
+	sliced, err := arr.Slice(1, 3)
+	if err != nil {
+		t.Fatalf("Slice failed: %v", err)
+	}
+	if sliced.Length() != 2 {
+		t.Fatalf("expected length 2, got %d", sliced.Length())
+	}
+	if v, _ := sliced.GetIdx(0); v.Integer() != 2 {
+		t.Errorf("expected 2, got %v", v)
+	}
+	if v, _ := sliced.GetIdx(1); v.Integer() != 3 {
+		t.Errorf("expected 3, got %v", v)
+	}
+}
+
+func TestArrayIterator(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	arr := runArrayScript(t, ctx, `["a", "b", "c"]`)
+
+	it := arr.Iterator()
+	var got []string
+	for {
+		idx, val, ok := it.Next()
+		if !ok {
+			break
+		}
+		if idx != uint32(len(got)) {
+			t.Errorf("expected idx %d, got %d", len(got), idx)
+		}
+		got = append(got, val.String())
+	}
+	if err := it.Err(); err != nil {
+		t.Fatalf("unexpected iterator error: %v", err)
+	}
+	want := []string{"a", "b", "c"}
+	if len(got) != len(want) {
+		t.Fatalf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, got)
+			break
+		}
+	}
+}
@@ -0,0 +1,615 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"time"
+)
+
+// CBOR major types, as defined by RFC 8949 section 3.1.
+const (
+	cborMajorUnsigned   = 0
+	cborMajorNegative   = 1
+	cborMajorByteString = 2
+	cborMajorTextString = 3
+	cborMajorArray      = 4
+	cborMajorMap        = 5
+	cborMajorTag        = 6
+	cborMajorSimple     = 7
+)
+
+// CBOR tags used by Marshal/UnmarshalCBOR.
+const (
+	cborTagDateEpoch   = 1
+	cborTagBigPositive = 2
+	cborTagBigNegative = 3
+	cborTagSet         = 258
+)
+
+// MarshalCBOR encodes this value as CBOR (RFC 8949). It is more compact
+// than MarshalJSON for numeric-heavy payloads and, unlike JSON, round-trips
+// BigInt (tag 2/3 bignums), Date (tag 1 epoch seconds), typed arrays (tag
+// 64-86), and Set (tag 258) without any of MarshalJSON's lossy string
+// coercions. A string-keyed Map is encoded as major type 5, the same as a
+// plain object, and UnmarshalCBOR reads it back as a plain object, not a Map.
+func (v *Value) MarshalCBOR() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := encodeCBOR(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func encodeCBOR(buf *bytes.Buffer, v *Value) error {
+	switch {
+	case v.IsUndefined():
+		buf.WriteByte(0xf7)
+		return nil
+	case v.IsNull():
+		buf.WriteByte(0xf6)
+		return nil
+	case v.IsBoolean():
+		if v.Boolean() {
+			buf.WriteByte(0xf5)
+		} else {
+			buf.WriteByte(0xf4)
+		}
+		return nil
+	case v.IsBigInt():
+		return encodeCBORBigInt(buf, v.BigInt())
+	case v.IsNumber():
+		return encodeCBORNumber(buf, v.Number())
+	case v.IsString():
+		s := v.String()
+		writeCBORHead(buf, cborMajorTextString, uint64(len(s)))
+		buf.WriteString(s)
+		return nil
+	case v.IsDate():
+		writeCBORHead(buf, cborMajorTag, cborTagDateEpoch)
+		return encodeCBORNumber(buf, v.Number()/1000)
+	case v.IsArrayBuffer():
+		b, err := v.ArrayBufferBytes()
+		if err != nil {
+			return err
+		}
+		writeCBORHead(buf, cborMajorByteString, uint64(len(b)))
+		buf.Write(b)
+		return nil
+	case v.IsUint8Array():
+		b, err := v.TypedArrayBytes()
+		if err != nil {
+			return err
+		}
+		writeCBORHead(buf, cborMajorByteString, uint64(len(b)))
+		buf.Write(b)
+		return nil
+	case v.IsTypedArray():
+		tag, ok := cborTypedArrayTag(v)
+		if !ok {
+			return errors.New("v8go: MarshalCBOR does not support this TypedArray kind")
+		}
+		b, err := v.TypedArrayBytes()
+		if err != nil {
+			return err
+		}
+		writeCBORHead(buf, cborMajorTag, tag)
+		writeCBORHead(buf, cborMajorByteString, uint64(len(b)))
+		buf.Write(b)
+		return nil
+	case v.IsSet():
+		return encodeCBORSet(buf, v)
+	case v.IsMap():
+		return encodeCBORMap(buf, v)
+	case v.IsArray():
+		return encodeCBORArray(buf, v)
+	case v.IsObject():
+		return encodeCBORObject(buf, v)
+	default:
+		return fmt.Errorf("v8go: MarshalCBOR does not support this value's type")
+	}
+}
+
+// cborTypedArrayTag maps a non-Uint8Array TypedArray to its RFC 8746 typed
+// array tag, preferring the little-endian variants since that's the native
+// byte order of the typed arrays this package can construct/read.
+func cborTypedArrayTag(v *Value) (uint64, bool) {
+	switch {
+	case v.IsUint8ClampedArray():
+		return 68, true
+	case v.IsInt8Array():
+		return 72, true
+	case v.IsUint16Array():
+		return 69, true
+	case v.IsInt16Array():
+		return 77, true
+	case v.IsUint32Array():
+		return 70, true
+	case v.IsInt32Array():
+		return 78, true
+	case v.IsBigUint64Array():
+		return 71, true
+	case v.IsBigInt64Array():
+		return 79, true
+	case v.IsFloat32Array():
+		return 85, true
+	case v.IsFloat64Array():
+		return 86, true
+	default:
+		return 0, false
+	}
+}
+
+func encodeCBORNumber(buf *bytes.Buffer, f float64) error {
+	if f == math.Trunc(f) && !math.IsInf(f, 0) {
+		if f >= 0 && f <= math.MaxInt64 {
+			writeCBORHead(buf, cborMajorUnsigned, uint64(f))
+			return nil
+		}
+		if f < 0 && f >= -math.MaxInt64 {
+			writeCBORHead(buf, cborMajorNegative, uint64(-f)-1)
+			return nil
+		}
+	}
+	buf.WriteByte(cborMajorSimple<<5 | 27)
+	var b [8]byte
+	binary.BigEndian.PutUint64(b[:], math.Float64bits(f))
+	buf.Write(b[:])
+	return nil
+}
+
+func encodeCBORBigInt(buf *bytes.Buffer, b *big.Int) error {
+	tag := uint64(cborTagBigPositive)
+	mag := b
+	if b.Sign() < 0 {
+		tag = cborTagBigNegative
+		mag = new(big.Int).Sub(new(big.Int).Neg(b), big.NewInt(1))
+	}
+	data := mag.Bytes()
+	writeCBORHead(buf, cborMajorTag, tag)
+	writeCBORHead(buf, cborMajorByteString, uint64(len(data)))
+	buf.Write(data)
+	return nil
+}
+
+func encodeCBORArray(buf *bytes.Buffer, v *Value) error {
+	obj, err := v.AsObject()
+	if err != nil {
+		return err
+	}
+	arr := &Array{Object: *obj}
+	length := arr.Length()
+	writeCBORHead(buf, cborMajorArray, uint64(length))
+	for i := uint32(0); i < length; i++ {
+		item, err := obj.GetIdx(i)
+		if err != nil {
+			return err
+		}
+		if err := encodeCBOR(buf, item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func encodeCBORObject(buf *bytes.Buffer, v *Value) error {
+	obj, err := v.AsObject()
+	if err != nil {
+		return err
+	}
+	keys := objectOwnKeys(obj)
+	writeCBORHead(buf, cborMajorMap, uint64(len(keys)))
+	for _, key := range keys {
+		writeCBORHead(buf, cborMajorTextString, uint64(len(key)))
+		buf.WriteString(key)
+		val, err := obj.Get(key)
+		if err != nil {
+			return err
+		}
+		if err := encodeCBOR(buf, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// encodeCBORSet encodes a JS Set as a tag-258 array, per
+// https://github.com/shanewholloway/js-cbor-codec/blob/master/docs/CBOR-262-tag258.md,
+// the de-facto convention this package follows for Set round-tripping.
+func encodeCBORSet(buf *bytes.Buffer, v *Value) error {
+	arr, err := arrayFromIterable(v)
+	if err != nil {
+		return err
+	}
+	writeCBORHead(buf, cborMajorTag, cborTagSet)
+	return encodeCBORArray(buf, arr)
+}
+
+// encodeCBORMap encodes a JS Map as a CBOR map (major type 5), the same
+// wire shape encodeCBORObject uses for plain objects. Every key must be a
+// JS string; a Map with any other key type is an error.
+func encodeCBORMap(buf *bytes.Buffer, v *Value) error {
+	arr, err := arrayFromIterable(v)
+	if err != nil {
+		return err
+	}
+	obj, err := arr.AsObject()
+	if err != nil {
+		return err
+	}
+	entries := &Array{Object: *obj}
+	n := entries.Length()
+	writeCBORHead(buf, cborMajorMap, uint64(n))
+	for i := uint32(0); i < n; i++ {
+		entry, err := obj.GetIdx(i)
+		if err != nil {
+			return err
+		}
+		entryObj, err := entry.AsObject()
+		if err != nil {
+			return err
+		}
+		key, err := entryObj.GetIdx(0)
+		if err != nil {
+			return err
+		}
+		if !key.IsString() {
+			return errors.New("v8go: MarshalCBOR only supports Map values with string keys")
+		}
+		val, err := entryObj.GetIdx(1)
+		if err != nil {
+			return err
+		}
+		s := key.String()
+		writeCBORHead(buf, cborMajorTextString, uint64(len(s)))
+		buf.WriteString(s)
+		if err := encodeCBOR(buf, val); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// arrayFromIterable converts a JS Set or Map into a plain JS Array via the
+// global Array.from, the same mechanism the rest of this package uses to
+// reach into well-known globals (see JSONParseWithReviver). A Set becomes an
+// array of its values; a Map becomes an array of its [key, value] entries.
+func arrayFromIterable(v *Value) (*Value, error) {
+	arrayNsVal, err := v.ctx.Global().Get("Array")
+	if err != nil {
+		return nil, err
+	}
+	arrayNs, err := arrayNsVal.AsObject()
+	if err != nil {
+		return nil, err
+	}
+	fromVal, err := arrayNs.Get("from")
+	if err != nil {
+		return nil, err
+	}
+	from, err := fromVal.AsFunction()
+	if err != nil {
+		return nil, err
+	}
+	return from.Call(Undefined(v.ctx.iso), v)
+}
+
+// writeCBORHead writes a major-type/argument head using the shortest
+// encoding RFC 8949 allows for n.
+func writeCBORHead(buf *bytes.Buffer, major byte, n uint64) {
+	switch {
+	case n < 24:
+		buf.WriteByte(major<<5 | byte(n))
+	case n <= 0xff:
+		buf.WriteByte(major<<5 | 24)
+		buf.WriteByte(byte(n))
+	case n <= 0xffff:
+		buf.WriteByte(major<<5 | 25)
+		var b [2]byte
+		binary.BigEndian.PutUint16(b[:], uint16(n))
+		buf.Write(b[:])
+	case n <= 0xffffffff:
+		buf.WriteByte(major<<5 | 26)
+		var b [4]byte
+		binary.BigEndian.PutUint32(b[:], uint32(n))
+		buf.Write(b[:])
+	default:
+		buf.WriteByte(major<<5 | 27)
+		var b [8]byte
+		binary.BigEndian.PutUint64(b[:], n)
+		buf.Write(b[:])
+	}
+}
+
+// UnmarshalCBOR decodes a single CBOR-encoded (RFC 8949) value within ctx.
+// It is the counterpart to Value.MarshalCBOR; see that doc comment for
+// which JS types round-trip. Indefinite-length items are not supported,
+// since MarshalCBOR never produces them.
+func UnmarshalCBOR(ctx *Context, data []byte) (*Value, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	d := &cborDecoder{ctx: ctx, data: data}
+	val, err := d.decodeValue()
+	if err != nil {
+		return nil, err
+	}
+	if d.pos != len(d.data) {
+		return nil, errors.New("v8go: UnmarshalCBOR: trailing bytes after value")
+	}
+	return val, nil
+}
+
+type cborDecoder struct {
+	ctx  *Context
+	data []byte
+	pos  int
+}
+
+func (d *cborDecoder) readByte() (byte, error) {
+	if d.pos >= len(d.data) {
+		return 0, errors.New("v8go: UnmarshalCBOR: unexpected end of input")
+	}
+	b := d.data[d.pos]
+	d.pos++
+	return b, nil
+}
+
+func (d *cborDecoder) readN(n int) ([]byte, error) {
+	if n < 0 || d.pos+n > len(d.data) {
+		return nil, errors.New("v8go: UnmarshalCBOR: unexpected end of input")
+	}
+	b := d.data[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// readHead reads one CBOR head and returns its major type, raw additional
+// info (0-31), and the decoded argument value.
+func (d *cborDecoder) readHead() (major byte, info byte, value uint64, err error) {
+	b, err := d.readByte()
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	major = b >> 5
+	info = b & 0x1f
+	switch {
+	case info < 24:
+		return major, info, uint64(info), nil
+	case info == 24:
+		raw, err := d.readN(1)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		return major, info, uint64(raw[0]), nil
+	case info == 25:
+		raw, err := d.readN(2)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		return major, info, uint64(binary.BigEndian.Uint16(raw)), nil
+	case info == 26:
+		raw, err := d.readN(4)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		return major, info, uint64(binary.BigEndian.Uint32(raw)), nil
+	case info == 27:
+		raw, err := d.readN(8)
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		return major, info, binary.BigEndian.Uint64(raw), nil
+	default:
+		return 0, 0, 0, fmt.Errorf("v8go: UnmarshalCBOR: unsupported additional info %d", info)
+	}
+}
+
+func (d *cborDecoder) decodeValue() (*Value, error) {
+	major, info, n, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+	switch major {
+	case cborMajorUnsigned:
+		return d.ctx.NewValue(n)
+	case cborMajorNegative:
+		if n > math.MaxInt64 {
+			mag := new(big.Int).SetUint64(n)
+			mag.Add(mag, big.NewInt(1))
+			mag.Neg(mag)
+			return d.ctx.NewValue(mag)
+		}
+		return d.ctx.NewValue(-(int64(n) + 1))
+	case cborMajorByteString:
+		raw, err := d.readN(int(n))
+		if err != nil {
+			return nil, err
+		}
+		b := make([]byte, len(raw))
+		copy(b, raw)
+		return d.ctx.NewValue(b)
+	case cborMajorTextString:
+		raw, err := d.readN(int(n))
+		if err != nil {
+			return nil, err
+		}
+		return d.ctx.NewValue(string(raw))
+	case cborMajorArray:
+		arr := d.ctx.NewArray(int(n))
+		for i := uint32(0); i < uint32(n); i++ {
+			elem, err := d.decodeValue()
+			if err != nil {
+				return nil, err
+			}
+			if err := arr.SetIdx(i, elem); err != nil {
+				return nil, err
+			}
+		}
+		return arr.Value, nil
+	case cborMajorMap:
+		obj := d.ctx.NewObject()
+		for i := uint64(0); i < n; i++ {
+			keyMajor, _, keyLen, err := d.readHead()
+			if err != nil {
+				return nil, err
+			}
+			if keyMajor != cborMajorTextString {
+				return nil, errors.New("v8go: UnmarshalCBOR: map keys must be text strings")
+			}
+			rawKey, err := d.readN(int(keyLen))
+			if err != nil {
+				return nil, err
+			}
+			val, err := d.decodeValue()
+			if err != nil {
+				return nil, err
+			}
+			if err := obj.Set(string(rawKey), val); err != nil {
+				return nil, err
+			}
+		}
+		return obj.Value, nil
+	case cborMajorTag:
+		return d.decodeTagged(n)
+	case cborMajorSimple:
+		return d.decodeSimple(info, n)
+	default:
+		return nil, fmt.Errorf("v8go: UnmarshalCBOR: unsupported major type %d", major)
+	}
+}
+
+func (d *cborDecoder) decodeSimple(info byte, n uint64) (*Value, error) {
+	if info < 24 {
+		switch n {
+		case 20:
+			return d.ctx.NewValue(false)
+		case 21:
+			return d.ctx.NewValue(true)
+		case 22:
+			return Null(d.ctx.iso), nil
+		case 23:
+			return Undefined(d.ctx.iso), nil
+		default:
+			return nil, fmt.Errorf("v8go: UnmarshalCBOR: unsupported simple value %d", n)
+		}
+	}
+	switch info {
+	case 26:
+		return d.ctx.NewValue(float64(math.Float32frombits(uint32(n))))
+	case 27:
+		return d.ctx.NewValue(math.Float64frombits(n))
+	default:
+		return nil, errors.New("v8go: UnmarshalCBOR: unsupported float width")
+	}
+}
+
+func (d *cborDecoder) decodeTagged(tag uint64) (*Value, error) {
+	switch tag {
+	case cborTagDateEpoch:
+		secs, err := d.decodeValue()
+		if err != nil {
+			return nil, err
+		}
+		return d.ctx.newDateValue(time.Unix(0, int64(secs.Number()*1e9)).UTC())
+	case cborTagBigPositive, cborTagBigNegative:
+		major, _, n, err := d.readHead()
+		if err != nil {
+			return nil, err
+		}
+		if major != cborMajorByteString {
+			return nil, errors.New("v8go: UnmarshalCBOR: bignum tag must be followed by a byte string")
+		}
+		raw, err := d.readN(int(n))
+		if err != nil {
+			return nil, err
+		}
+		mag := new(big.Int).SetBytes(raw)
+		if tag == cborTagBigNegative {
+			mag.Add(mag, big.NewInt(1))
+			mag.Neg(mag)
+		}
+		return d.ctx.NewValue(mag)
+	case cborTagSet:
+		arr, err := d.decodeValue()
+		if err != nil {
+			return nil, err
+		}
+		return newSetFromArray(d.ctx, arr)
+	default:
+		if kind, ok := cborTypedArrayKind(tag); ok {
+			return d.decodeTypedArray(kind)
+		}
+		return nil, fmt.Errorf("v8go: UnmarshalCBOR: unsupported tag %d", tag)
+	}
+}
+
+// decodeTypedArray reads the byte string following a typed-array tag and
+// reconstructs the TypedArray of the given kind from its raw bytes.
+func (d *cborDecoder) decodeTypedArray(kind string) (*Value, error) {
+	major, _, n, err := d.readHead()
+	if err != nil {
+		return nil, err
+	}
+	if major != cborMajorByteString {
+		return nil, errors.New("v8go: UnmarshalCBOR: typed array tag must be followed by a byte string")
+	}
+	raw, err := d.readN(int(n))
+	if err != nil {
+		return nil, err
+	}
+	b := make([]byte, len(raw))
+	copy(b, raw)
+	return d.ctx.newTypedArrayFromBytes(kind, b)
+}
+
+// cborTypedArrayKind maps an RFC 8746 typed array tag back to the TypedArray
+// kind name newTypedArrayFromBytes expects, the inverse of cborTypedArrayTag.
+func cborTypedArrayKind(tag uint64) (string, bool) {
+	switch tag {
+	case 68:
+		return "Uint8ClampedArray", true
+	case 72:
+		return "Int8Array", true
+	case 69:
+		return "Uint16Array", true
+	case 77:
+		return "Int16Array", true
+	case 70:
+		return "Uint32Array", true
+	case 78:
+		return "Int32Array", true
+	case 71:
+		return "BigUint64Array", true
+	case 79:
+		return "BigInt64Array", true
+	case 85:
+		return "Float32Array", true
+	case 86:
+		return "Float64Array", true
+	default:
+		return "", false
+	}
+}
+
+// newSetFromArray builds a JS Set from an array of decoded values, via the
+// global Set constructor — the decode-side counterpart to
+// arrayFromIterable's use of Array.from on the encode side.
+func newSetFromArray(ctx *Context, arr *Value) (*Value, error) {
+	setCtorVal, err := ctx.Global().Get("Set")
+	if err != nil {
+		return nil, err
+	}
+	setCtor, err := setCtorVal.AsFunction()
+	if err != nil {
+		return nil, err
+	}
+	return setCtor.NewInstance(arr)
+}
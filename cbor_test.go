@@ -0,0 +1,212 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	v8go "rogchap.com/v8go"
+)
+
+func TestValueMarshalCBORRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({a: 1, b: "two", c: [1, 2, 3], d: true, e: null})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := val.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR failed: %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("expected a non-empty CBOR buffer")
+	}
+
+	rtn, err := v8go.UnmarshalCBOR(ctx, data)
+	if err != nil {
+		t.Fatalf("UnmarshalCBOR failed: %v", err)
+	}
+	obj, err := rtn.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	if a, _ := obj.Get("a"); a.Integer() != 1 {
+		t.Errorf("expected a == 1, got %v", a)
+	}
+	if d, _ := obj.Get("d"); !d.Boolean() {
+		t.Errorf("expected d == true, got %v", d)
+	}
+}
+
+func TestValueMarshalCBORBigInt(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	want := new(big.Int)
+	want.SetString("123456789012345678901234567890", 10)
+	val, err := ctx.NewValue(want)
+	if err != nil {
+		t.Fatalf("NewValue failed: %v", err)
+	}
+
+	data, err := val.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR failed: %v", err)
+	}
+
+	rtn, err := v8go.UnmarshalCBOR(ctx, data)
+	if err != nil {
+		t.Fatalf("UnmarshalCBOR failed: %v", err)
+	}
+	if got := rtn.BigInt(); got.Cmp(want) != 0 {
+		t.Errorf("got %s, want %s", got, want)
+	}
+}
+
+func TestValueMarshalCBORBytes(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	want := []byte{1, 2, 3, 4, 5}
+	val, err := ctx.NewArrayBuffer(want)
+	if err != nil {
+		t.Fatalf("NewArrayBuffer failed: %v", err)
+	}
+
+	data, err := val.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR failed: %v", err)
+	}
+
+	rtn, err := v8go.UnmarshalCBOR(ctx, data)
+	if err != nil {
+		t.Fatalf("UnmarshalCBOR failed: %v", err)
+	}
+	got, err := rtn.TypedArrayBytes()
+	if err != nil {
+		t.Fatalf("TypedArrayBytes failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestValueMarshalCBORTypedArray(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`new Int32Array([1, -2, 3, -4])`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := val.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR failed: %v", err)
+	}
+
+	rtn, err := v8go.UnmarshalCBOR(ctx, data)
+	if err != nil {
+		t.Fatalf("UnmarshalCBOR failed: %v", err)
+	}
+	if !rtn.IsInt32Array() {
+		t.Fatalf("expected an Int32Array, got %v", rtn)
+	}
+	got, err := rtn.TypedArrayBytes()
+	if err != nil {
+		t.Fatalf("TypedArrayBytes failed: %v", err)
+	}
+	want, err := val.TypedArrayBytes()
+	if err != nil {
+		t.Fatalf("TypedArrayBytes failed: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestValueMarshalCBORSet(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`new Set([1, 2, 3])`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := val.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR failed: %v", err)
+	}
+
+	rtn, err := v8go.UnmarshalCBOR(ctx, data)
+	if err != nil {
+		t.Fatalf("UnmarshalCBOR failed: %v", err)
+	}
+	if !rtn.IsSet() {
+		t.Fatalf("expected a Set, got %v", rtn)
+	}
+}
+
+func TestValueMarshalCBORMap(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`new Map([["a", 1], ["b", 2]])`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := val.MarshalCBOR()
+	if err != nil {
+		t.Fatalf("MarshalCBOR failed: %v", err)
+	}
+
+	rtn, err := v8go.UnmarshalCBOR(ctx, data)
+	if err != nil {
+		t.Fatalf("UnmarshalCBOR failed: %v", err)
+	}
+	obj, err := rtn.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	if a, _ := obj.Get("a"); a.Integer() != 1 {
+		t.Errorf("expected a == 1, got %v", a)
+	}
+	if b, _ := obj.Get("b"); b.Integer() != 2 {
+		t.Errorf("expected b == 2, got %v", b)
+	}
+}
+
+func TestUnmarshalCBORRequiresContext(t *testing.T) {
+	t.Parallel()
+
+	if _, err := v8go.UnmarshalCBOR(nil, []byte{}); err == nil {
+		t.Error("expected error but got <nil>")
+	}
+}
@@ -0,0 +1,402 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include <stdlib.h>
+// #include "v8go.h"
+import "C"
+import (
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// objectOwnKeys returns the object's own enumerable string-keyed property
+// names, in insertion order. It underlies Export/ExportTo's struct, map,
+// and plain-object handling, and Object.GetOwnPropertyNames.
+func objectOwnKeys(o *Object) []string {
+	rtn := C.ObjectGetOwnPropertyNames(o.valuePtr())
+	if rtn.count == 0 {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(rtn.names))
+	cNames := (*[1 << 20]*C.char)(unsafe.Pointer(rtn.names))[:rtn.count:rtn.count]
+	names := make([]string, rtn.count)
+	for i, cName := range cNames {
+		names[i] = C.GoString(cName)
+		C.free(unsafe.Pointer(cName))
+	}
+	return names
+}
+
+// parseJSONTag resolves the property name and omitempty-ness that
+// ExportTo/NewValue should use for a struct field, honoring the same
+// `json:"name,omitempty"` / `json:"-"` conventions as encoding/json.
+func parseJSONTag(tag, fieldName string) (name string, omitempty bool) {
+	if tag == "" {
+		return fieldName, false
+	}
+	parts := strings.Split(tag, ",")
+	name = parts[0]
+	if name == "" {
+		name = fieldName
+	}
+	for _, opt := range parts[1:] {
+		if opt == "omitempty" {
+			omitempty = true
+		}
+	}
+	return name, omitempty
+}
+
+// Export converts this JS value into a natural, untyped Go representation,
+// modeled on goja's Value.Export. Primitives map to their natural Go
+// counterparts (bool, float64, string), BigInt to *big.Int, Date to
+// time.Time, arrays to []interface{}, plain objects to
+// map[string]interface{}, and Uint8Array/ArrayBuffer to []byte. The other
+// TypedArray kinds also export as []byte, holding their raw element bytes.
+// Functions and symbols are returned unchanged as the *Value itself; any
+// other object, including exotic ones such as Map or Set, is exported like
+// a plain object, from its own enumerable properties.
+//
+// Cyclic object graphs are detected via an identity map keyed on the
+// underlying V8 handle, so a self-referential array or object is exported
+// as a Go value that refers back to itself rather than recursing forever.
+func (v *Value) Export() interface{} {
+	return v.export(make(map[C.ValuePtr]interface{}))
+}
+
+func (v *Value) export(seen map[C.ValuePtr]interface{}) interface{} {
+	switch {
+	case v.IsUndefined(), v.IsNull():
+		return nil
+	case v.IsBoolean():
+		return v.Boolean()
+	case v.IsBigInt():
+		return v.BigInt()
+	case v.IsNumber():
+		return v.Number()
+	case v.IsString():
+		return v.String()
+	case v.IsDate():
+		return v.exportDate()
+	case v.IsArrayBuffer():
+		if b, err := v.ArrayBufferBytes(); err == nil {
+			return b
+		}
+		return v
+	case v.IsTypedArray():
+		if b, err := v.TypedArrayBytes(); err == nil {
+			return b
+		}
+		return v
+	case v.IsFunction():
+		return v
+	case v.IsArray():
+		return v.exportArray(seen)
+	case v.IsObject():
+		return v.exportObject(seen)
+	default:
+		return v
+	}
+}
+
+func (v *Value) exportDate() interface{} {
+	ms := v.Number()
+	return time.UnixMilli(int64(ms)).UTC()
+}
+
+func (v *Value) exportArray(seen map[C.ValuePtr]interface{}) interface{} {
+	ptr := v.valuePtr()
+	if existing, ok := seen[ptr]; ok {
+		return existing
+	}
+	obj, err := v.AsObject()
+	if err != nil {
+		return v
+	}
+	arr := &Array{Object: *obj}
+	length := arr.Length()
+	result := make([]interface{}, length)
+	seen[ptr] = result
+	for idx := uint32(0); idx < length; idx++ {
+		item, err := obj.GetIdx(idx)
+		if err != nil {
+			continue
+		}
+		result[idx] = item.export(seen)
+	}
+	return result
+}
+
+func (v *Value) exportObject(seen map[C.ValuePtr]interface{}) interface{} {
+	ptr := v.valuePtr()
+	if existing, ok := seen[ptr]; ok {
+		return existing
+	}
+	obj, err := v.AsObject()
+	if err != nil {
+		return v
+	}
+	result := make(map[string]interface{})
+	seen[ptr] = result
+	for _, key := range objectOwnKeys(obj) {
+		val, err := obj.Get(key)
+		if err != nil {
+			continue
+		}
+		result[key] = val.export(seen)
+	}
+	return result
+}
+
+// asInt64 converts a JS Number or BigInt to an int64, erroring instead of
+// silently truncating the way Integer() does. It's a thin wrapper around
+// TryInt64, kept as its own method so ExportTo's call sites don't need to
+// know about RangeError/TypeError.
+func (v *Value) asInt64() (int64, error) {
+	return v.TryInt64()
+}
+
+// asUint64 converts a JS Number or BigInt to a uint64, erroring instead of
+// silently truncating. See asInt64.
+func (v *Value) asUint64() (uint64, error) {
+	return v.TryUint64()
+}
+
+// ExportTo populates dst, which must be a non-nil pointer, from this JS
+// value using reflection: JS arrays become Go slices/arrays, JS objects
+// become Go structs (matched by field name or `json` tag) or maps, JS
+// numbers become any numeric kind (with an error on overflow), JS null and
+// undefined become a nil pointer, and JS functions become a Go func value
+// whose calls are forwarded to Function.Call.
+func (v *Value) ExportTo(dst interface{}) error {
+	rv := reflect.ValueOf(dst)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return fmt.Errorf("v8go: ExportTo requires a non-nil pointer, got %T", dst)
+	}
+	return v.assignTo(rv.Elem())
+}
+
+// Unmarshal populates dst, which must be a non-nil pointer, from this JS
+// value. It is an alias for ExportTo, kept for callers coming from
+// encoding/json who expect an Unmarshal entry point alongside NewValue's
+// reflection-based encoding.
+func (v *Value) Unmarshal(dst interface{}) error {
+	return v.ExportTo(dst)
+}
+
+func (v *Value) assignTo(dst reflect.Value) error {
+	if v.IsNullOrUndefined() {
+		switch dst.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Map, reflect.Slice:
+			dst.Set(reflect.Zero(dst.Type()))
+			return nil
+		default:
+			return fmt.Errorf("v8go: cannot assign null/undefined to %s", dst.Type())
+		}
+	}
+
+	if dst.Kind() == reflect.Ptr {
+		if dst.IsNil() {
+			dst.Set(reflect.New(dst.Type().Elem()))
+		}
+		return v.assignTo(dst.Elem())
+	}
+
+	switch dst.Type() {
+	case reflect.TypeOf(big.Int{}):
+		if !v.IsBigInt() {
+			return fmt.Errorf("v8go: cannot assign non-BigInt value to big.Int")
+		}
+		dst.Set(reflect.ValueOf(*v.BigInt()))
+		return nil
+	case reflect.TypeOf(time.Time{}):
+		if !v.IsDate() {
+			return fmt.Errorf("v8go: cannot assign non-Date value to time.Time")
+		}
+		dst.Set(reflect.ValueOf(v.exportDate()))
+		return nil
+	}
+
+	if dst.Kind() == reflect.Interface && dst.NumMethod() == 0 {
+		dst.Set(reflect.ValueOf(v.Export()))
+		return nil
+	}
+
+	switch dst.Kind() {
+	case reflect.Bool:
+		dst.SetBool(v.Boolean())
+		return nil
+	case reflect.String:
+		dst.SetString(v.String())
+		return nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := v.asInt64()
+		if err != nil {
+			return err
+		}
+		if dst.OverflowInt(n) {
+			return fmt.Errorf("v8go: value %d overflows %s", n, dst.Type())
+		}
+		dst.SetInt(n)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := v.asUint64()
+		if err != nil {
+			return err
+		}
+		if dst.OverflowUint(n) {
+			return fmt.Errorf("v8go: value %d overflows %s", n, dst.Type())
+		}
+		dst.SetUint(n)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		if !v.IsNumber() {
+			return fmt.Errorf("v8go: cannot assign non-Number value to %s", dst.Type())
+		}
+		dst.SetFloat(v.Number())
+		return nil
+	case reflect.Slice, reflect.Array:
+		return v.assignToSequence(dst)
+	case reflect.Map:
+		return v.assignToMap(dst)
+	case reflect.Struct:
+		return v.assignToStruct(dst)
+	case reflect.Func:
+		return v.assignToFunc(dst)
+	default:
+		return fmt.Errorf("v8go: ExportTo does not support destination kind %s", dst.Kind())
+	}
+}
+
+func (v *Value) assignToSequence(dst reflect.Value) error {
+	if !v.IsArray() {
+		return fmt.Errorf("v8go: cannot assign non-Array value to %s", dst.Type())
+	}
+	obj, err := v.AsObject()
+	if err != nil {
+		return err
+	}
+	arr := &Array{Object: *obj}
+	length := int(arr.Length())
+
+	if dst.Kind() == reflect.Slice {
+		dst.Set(reflect.MakeSlice(dst.Type(), length, length))
+	} else if length > dst.Len() {
+		return fmt.Errorf("v8go: array of length %d does not fit in %s", length, dst.Type())
+	}
+
+	for idx := 0; idx < length; idx++ {
+		item, err := obj.GetIdx(uint32(idx))
+		if err != nil {
+			return err
+		}
+		if err := item.assignTo(dst.Index(idx)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *Value) assignToMap(dst reflect.Value) error {
+	if !v.IsObject() {
+		return fmt.Errorf("v8go: cannot assign non-object value to %s", dst.Type())
+	}
+	obj, err := v.AsObject()
+	if err != nil {
+		return err
+	}
+	elemType := dst.Type().Elem()
+	m := reflect.MakeMap(dst.Type())
+	for _, key := range objectOwnKeys(obj) {
+		val, err := obj.Get(key)
+		if err != nil {
+			return err
+		}
+		elem := reflect.New(elemType).Elem()
+		if err := val.assignTo(elem); err != nil {
+			return err
+		}
+		m.SetMapIndex(reflect.ValueOf(key), elem)
+	}
+	dst.Set(m)
+	return nil
+}
+
+func (v *Value) assignToStruct(dst reflect.Value) error {
+	if !v.IsObject() {
+		return fmt.Errorf("v8go: cannot assign non-object value to %s", dst.Type())
+	}
+	obj, err := v.AsObject()
+	if err != nil {
+		return err
+	}
+	t := dst.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue // unexported
+		}
+		name, _ := parseJSONTag(field.Tag.Get("json"), field.Name)
+		if name == "-" {
+			continue
+		}
+		if !obj.Has(name) {
+			continue
+		}
+		val, err := obj.Get(name)
+		if err != nil {
+			return err
+		}
+		if err := val.assignTo(dst.Field(i)); err != nil {
+			return fmt.Errorf("v8go: field %q: %w", field.Name, err)
+		}
+	}
+	return nil
+}
+
+func (v *Value) assignToFunc(dst reflect.Value) error {
+	if !v.IsFunction() {
+		return fmt.Errorf("v8go: cannot assign non-Function value to %s", dst.Type())
+	}
+	fn, err := v.AsFunction()
+	if err != nil {
+		return err
+	}
+	t := dst.Type()
+	wrapped := reflect.MakeFunc(t, func(args []reflect.Value) []reflect.Value {
+		jsArgs := make([]Valuer, len(args))
+		for i, a := range args {
+			val, err := v.ctx.NewValue(a.Interface())
+			if err != nil {
+				panic(err)
+			}
+			jsArgs[i] = val
+		}
+		rtn, err := fn.Call(Undefined(v.ctx.iso), jsArgs...)
+		out := make([]reflect.Value, t.NumOut())
+		for i := range out {
+			out[i] = reflect.Zero(t.Out(i))
+		}
+		if t.NumOut() > 0 {
+			if err == nil {
+				ov := reflect.New(t.Out(0)).Elem()
+				if assignErr := rtn.assignTo(ov); assignErr == nil {
+					out[0] = ov
+				}
+			}
+		}
+		if t.NumOut() > 1 && err != nil {
+			out[t.NumOut()-1] = reflect.ValueOf(&err).Elem()
+		}
+		return out
+	})
+	dst.Set(wrapped)
+	return nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"reflect"
+	"testing"
+
+	v8go "rogchap.com/v8go"
+)
+
+func TestValueExport(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({a: 1, b: "two", c: [3, 4, 5]})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := val.Export()
+	want := map[string]interface{}{
+		"a": float64(1),
+		"b": "two",
+		"c": []interface{}{float64(3), float64(4), float64(5)},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Export() = %#v, want %#v", got, want)
+	}
+}
+
+func TestValueExportCycle(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`(function() { let o = {}; o.self = o; return o; })()`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, ok := val.Export().(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected map[string]interface{}, got %T", val.Export())
+	}
+	if got["self"].(map[string]interface{})["self"] == nil {
+		t.Error("expected cyclic export to refer back to itself, not stack overflow")
+	}
+}
+
+func TestValueExportTo(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	type person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	val, err := ctx.RunScript(`({name: "Ada", age: 36})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var p person
+	if err := val.ExportTo(&p); err != nil {
+		t.Fatalf("ExportTo failed: %v", err)
+	}
+	if p.Name != "Ada" || p.Age != 36 {
+		t.Errorf("unexpected struct %+v", p)
+	}
+}
+
+func TestValueUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	type person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+
+	val, err := ctx.RunScript(`({name: "Grace", age: 79})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var p person
+	if err := val.Unmarshal(&p); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if p.Name != "Grace" || p.Age != 79 {
+		t.Errorf("unexpected struct %+v", p)
+	}
+}
+
+func TestValueExportToOverflow(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`300`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var b int8
+	if err := val.ExportTo(&b); err == nil {
+		t.Error("expected overflow error, got <nil>")
+	}
+}
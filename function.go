@@ -7,7 +7,10 @@ package v8go
 // #include "v8go.h"
 import "C"
 import (
+	"context"
+	"fmt"
 	"runtime"
+	"sync/atomic"
 	"unsafe"
 )
 
@@ -43,6 +46,68 @@ func (fn *Function) NewInstance(args ...Valuer) (*Object, error) {
 	return objectResult(fn.ctx, rtn)
 }
 
+// CallWithContext behaves like Call, but also watches ctx: if ctx is
+// cancelled or its deadline elapses before the JS call returns, the
+// isolate's execution is forcefully terminated via Isolate.TerminateExecution
+// and CallWithContext returns an error that wraps ctx.Err(), distinguishable
+// from an ordinary JS exception. Either way, the isolate is left able to run
+// further scripts: a terminated call always has CancelTerminateExecution
+// called on its way out.
+func (fn *Function) CallWithContext(ctx context.Context, recv Valuer, args ...Valuer) (*Value, error) {
+	stopWatch := watchForCancellation(ctx, fn.ctx.iso)
+	rtn, err := fn.Call(recv, args...)
+	if stopWatch() {
+		fn.ctx.iso.CancelTerminateExecution()
+		return nil, fmt.Errorf("v8go: execution terminated: %w", ctx.Err())
+	}
+	return rtn, err
+}
+
+// NewInstanceWithContext behaves like NewInstance, but also watches ctx as
+// CallWithContext does, terminating the constructor call if ctx is
+// cancelled or its deadline elapses.
+func (fn *Function) NewInstanceWithContext(ctx context.Context, args ...Valuer) (*Object, error) {
+	stopWatch := watchForCancellation(ctx, fn.ctx.iso)
+	rtn, err := fn.NewInstance(args...)
+	if stopWatch() {
+		fn.ctx.iso.CancelTerminateExecution()
+		return nil, fmt.Errorf("v8go: execution terminated: %w", ctx.Err())
+	}
+	return rtn, err
+}
+
+// watchForCancellation starts a goroutine that calls iso.TerminateExecution
+// if ctx is done before the returned stop function is called. stop
+// unregisters the watcher and reports whether it ended up terminating
+// execution, so the caller can decide whether to call
+// Isolate.CancelTerminateExecution and surface ctx.Err().
+//
+// stop blocks until the watcher goroutine has fully exited before reading
+// terminated, rather than just signalling it to stop. Without that, a ctx
+// that fires right as the call returns could have the goroutine take the
+// ctx.Done() branch and call TerminateExecution after stop already read
+// terminated == 0 and returned false, leaving the isolate terminating with
+// no CancelTerminateExecution call to match it.
+func watchForCancellation(ctx context.Context, iso *Isolate) (stop func() (terminated bool)) {
+	done := make(chan struct{})
+	exited := make(chan struct{})
+	var terminated int32
+	go func() {
+		defer close(exited)
+		select {
+		case <-ctx.Done():
+			atomic.StoreInt32(&terminated, 1)
+			iso.TerminateExecution()
+		case <-done:
+		}
+	}()
+	return func() bool {
+		close(done)
+		<-exited
+		return atomic.LoadInt32(&terminated) == 1
+	}
+}
+
 // Return the source map url for a function.
 func (fn *Function) SourceMapUrl() *Value {
 	ptr := C.FunctionSourceMapUrl(fn.valuePtr())
@@ -0,0 +1,106 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	v8go "rogchap.com/v8go"
+)
+
+func TestFunctionCallWithContextDeadline(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`(function() { while (true) {} })`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fn, err := val.AsFunction()
+	if err != nil {
+		t.Fatalf("AsFunction failed: %v", err)
+	}
+
+	deadline, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	_, err = fn.CallWithContext(deadline, v8go.Undefined(ctx.Isolate()))
+	if err == nil {
+		t.Fatal("expected an error from a deadline-exceeded call, got <nil>")
+	}
+
+	// The isolate should still be usable afterwards.
+	if _, err := ctx.RunScript(`1 + 1`, "after.js"); err != nil {
+		t.Errorf("isolate unusable after cancellation: %v", err)
+	}
+}
+
+func TestFunctionCallWithContextCooperativeCancel(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`(function fib(n) { return n < 2 ? n : fib(n-1) + fib(n-2); })`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fn, err := val.AsFunction()
+	if err != nil {
+		t.Fatalf("AsFunction failed: %v", err)
+	}
+
+	cancelCtx, cancel := context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+
+	n, err := ctx.NewValue(35)
+	if err != nil {
+		t.Fatalf("NewValue failed: %v", err)
+	}
+	_, err = fn.CallWithContext(cancelCtx, v8go.Undefined(ctx.Isolate()), n)
+	if err == nil {
+		t.Error("expected an error from a cancelled call, got <nil>")
+	}
+}
+
+// TestFunctionCallWithContextRaceOnReturn exercises the race window where
+// ctx fires at roughly the same instant the call returns on its own: the
+// isolate must come out of CallWithContext able to run further scripts
+// either way, never left mid-termination.
+func TestFunctionCallWithContextRaceOnReturn(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`(function() { return 1 + 1; })`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fn, err := val.AsFunction()
+	if err != nil {
+		t.Fatalf("AsFunction failed: %v", err)
+	}
+
+	for i := 0; i < 200; i++ {
+		callCtx, cancel := context.WithTimeout(context.Background(), time.Microsecond)
+		_, _ = fn.CallWithContext(callCtx, v8go.Undefined(ctx.Isolate()))
+		cancel()
+
+		if _, err := ctx.RunScript(`1 + 1`, "after.js"); err != nil {
+			t.Fatalf("isolate unusable after cancellation race (iteration %d): %v", i, err)
+		}
+	}
+}
@@ -22,6 +22,12 @@ import (
 
 var v8once sync.Once
 
+// isolatesByPtr lets C trampolines that are only handed back a raw
+// IsolatePtr (no room for a Go-side void* payload) recover the owning
+// *Isolate. Populated in NewIsolateWithOptions and NewIsolateFromSnapshot,
+// and cleared in Dispose.
+var isolatesByPtr sync.Map // C.IsolatePtr -> *Isolate
+
 // Isolate is a JavaScript VM instance with its own heap and
 // garbage collector. Most applications will create one isolate
 // with many V8 contexts for execution.
@@ -36,6 +42,12 @@ type Isolate struct {
 	cbSeq   int                      // Latest ID assigned to a callback
 	cbs     map[int]FunctionCallback // Array of registered callbacks
 
+	nearHeapLimitCB NearHeapLimitCallback // Installed by SetNearHeapLimitCallback
+	oomErrorHandler OOMErrorHandler       // Installed by SetOOMErrorHandler
+
+	gcPrologueCBs []GCCallback // Registered by AddGCPrologueCallback
+	gcEpilogueCBs []GCCallback // Registered by AddGCEpilogueCallback
+
 	stringBuffer []byte // Temporary scratch space for cgo to copy strings to
 
 	null      *Value // Cached Value of `null`
@@ -79,10 +91,31 @@ func NewIsolate() *Isolate {
 // The heap sizes are given in bytes. If both are zero, the default
 // heap settings are used.
 func NewIsolateWith(initialHeap uint64, maxHeap uint64) *Isolate {
+	return NewIsolateWithOptions(IsolateOptions{
+		InitialHeapSize: initialHeap,
+		MaxHeapSize:     maxHeap,
+	})
+}
+
+// NewIsolateWithOptions creates a new V8 isolate using the full set of V8
+// ResourceConstraints carried by opts, rather than the two coarse heap-size
+// knobs exposed by NewIsolateWith. Any field left at its zero value lets V8
+// fall back to its own autosizing heuristics for that knob.
+func NewIsolateWithOptions(opts IsolateOptions) *Isolate {
 	v8once.Do(func() {
 		C.Init()
 	})
-	result := C.NewIsolate(C.ulong(initialHeap), C.ulong(maxHeap))
+	cOpts := C.IsolateOptions{
+		initial_heap_size:                C.ulong(opts.InitialHeapSize),
+		max_heap_size:                    C.ulong(opts.MaxHeapSize),
+		max_old_space_size_mb:            C.ulong(opts.MaxOldSpaceSizeMB),
+		max_young_generation_size_mb:     C.ulong(opts.MaxYoungGenerationSizeMB),
+		initial_old_space_size_mb:        C.ulong(opts.InitialOldSpaceSizeMB),
+		initial_young_generation_size_mb: C.ulong(opts.InitialYoungGenerationSizeMB),
+		code_range_size_mb:               C.ulong(opts.CodeRangeSizeMB),
+		stack_limit:                      C.ulong(opts.StackLimit),
+	}
+	result := C.NewIsolateWithOptions(cOpts)
 	iso := &Isolate{
 		ptr:          result.isolate,
 		cbs:          make(map[int]FunctionCallback),
@@ -96,6 +129,7 @@ func NewIsolateWith(initialHeap uint64, maxHeap uint64) *Isolate {
 	iso.undefined = &Value{result.undefinedVal, iso.internalContext}
 	iso.falseVal = &Value{result.falseVal, iso.internalContext}
 	iso.trueVal = &Value{result.trueVal, iso.internalContext}
+	isolatesByPtr.Store(iso.ptr, iso)
 	return iso
 }
 
@@ -105,6 +139,14 @@ func (i *Isolate) TerminateExecution() {
 	C.IsolateTerminateExecution(i.ptr)
 }
 
+// CancelTerminateExecution resumes execution capability on the isolate
+// after a call to TerminateExecution. It must be called once the isolate
+// has unwound past the point where the execution was terminated, or
+// subsequent attempts to run JS on it will continue to fail.
+func (i *Isolate) CancelTerminateExecution() {
+	C.IsolateCancelTerminateExecution(i.ptr)
+}
+
 // IsExecutionTerminating returns whether V8 is currently terminating
 // Javascript execution. If true, there are still JavaScript frames
 // on the stack and the termination exception is still active.
@@ -178,6 +220,7 @@ func (i *Isolate) Dispose() {
 	if i.v8Lock != nil {
 		i.Unlock()
 	}
+	isolatesByPtr.Delete(i.ptr)
 	C.IsolateDispose(i.ptr)
 	i.ptr = nil
 }
@@ -247,3 +290,163 @@ func (i *Isolate) getCallback(ref int) FunctionCallback {
 	defer i.cbMutex.RUnlock()
 	return i.cbs[ref]
 }
+
+// NearHeapLimitCallback is invoked by V8 just before it would otherwise abort
+// the process because the isolate's heap has grown to its limit. current and
+// initial are the isolate's current and initial heap limits, in bytes. The
+// returned value becomes the isolate's new heap limit: return a value larger
+// than current to buy time (e.g. to call Isolate.TerminateExecution, dump a
+// heap snapshot, or emit metrics) before V8 tries again, or return current
+// unchanged to let the original out-of-memory condition proceed.
+type NearHeapLimitCallback func(current, initial uint64) uint64
+
+// SetNearHeapLimitCallback installs a callback that V8 invokes when the
+// isolate's heap usage approaches its limit, just before V8 would otherwise
+// abort the process with an out-of-memory error. Only one callback may be
+// installed at a time; installing a new one replaces the previous one.
+func (i *Isolate) SetNearHeapLimitCallback(cb NearHeapLimitCallback) {
+	i.nearHeapLimitCB = cb
+	C.IsolateSetNearHeapLimitCallback(i.ptr)
+}
+
+// RemoveNearHeapLimitCallback uninstalls the callback set by
+// SetNearHeapLimitCallback. heapLimit, if non-zero, is passed through to V8
+// as the heap limit to reset to once the callback has been removed.
+func (i *Isolate) RemoveNearHeapLimitCallback(heapLimit uint64) {
+	C.IsolateRemoveNearHeapLimitCallback(i.ptr, C.ulong(heapLimit))
+	i.nearHeapLimitCB = nil
+}
+
+//export goNearHeapLimitCallback
+func goNearHeapLimitCallback(isoPtr C.IsolatePtr, current, initial C.size_t) C.size_t {
+	v, ok := isolatesByPtr.Load(isoPtr)
+	if !ok {
+		return current
+	}
+	iso := v.(*Isolate)
+	if iso.nearHeapLimitCB == nil {
+		return current
+	}
+	return C.size_t(iso.nearHeapLimitCB(uint64(current), uint64(initial)))
+}
+
+// OOMErrorHandler is invoked when V8 detects that an isolate has run out of
+// memory. location describes where the failure occurred; isHeapOOM is true
+// for a JS heap exhaustion and false for a failure to allocate memory
+// outside the heap (e.g. for internal V8 bookkeeping). By the time this is
+// called V8 considers the isolate unusable: the handler should log/alert and
+// then Dispose the isolate rather than try to keep using it.
+type OOMErrorHandler func(location string, isHeapOOM bool)
+
+// SetOOMErrorHandler installs a handler that V8 invokes, in place of its
+// default behavior of aborting the process, when the isolate runs out of
+// memory. Unlike SetNearHeapLimitCallback, which can still try to avert the
+// failure, this handler only gets to observe and report it: the isolate must
+// be considered unusable once it has run, and the handler should Dispose it.
+func (i *Isolate) SetOOMErrorHandler(handler OOMErrorHandler) {
+	i.oomErrorHandler = handler
+	C.IsolateSetOOMErrorHandler(i.ptr)
+}
+
+//export goOOMErrorCallback
+func goOOMErrorCallback(isoPtr C.IsolatePtr, location *C.char, isHeapOOM C.int) {
+	v, ok := isolatesByPtr.Load(isoPtr)
+	if !ok {
+		return
+	}
+	iso := v.(*Isolate)
+	if iso.oomErrorHandler == nil {
+		return
+	}
+	iso.oomErrorHandler(C.GoString(location), isHeapOOM != 0)
+}
+
+// GCType identifies which kind of garbage collection pass is running,
+// matching V8's v8::GCType bitmask.
+type GCType int
+
+const (
+	GCTypeScavenge           GCType = 1 << 0
+	GCTypeMarkSweepCompact   GCType = 1 << 1
+	GCTypeIncrementalMarking GCType = 1 << 2
+	GCTypeWeakCallbacks      GCType = 1 << 3
+	GCTypeAll                GCType = GCTypeScavenge | GCTypeMarkSweepCompact | GCTypeIncrementalMarking | GCTypeWeakCallbacks
+)
+
+// GCCallbackFlags carries extra detail about a GC pass, matching V8's
+// v8::GCCallbackFlags bitmask.
+type GCCallbackFlags int
+
+const (
+	GCCallbackFlagForced                        GCCallbackFlags = 1 << 2
+	GCCallbackFlagCollectAllAvailableGarbage    GCCallbackFlags = 1 << 4
+	GCCallbackFlagCollectAllExternalMemory      GCCallbackFlags = 1 << 5
+	GCCallbackFlagScheduleIdleGarbageCollection GCCallbackFlags = 1 << 6
+)
+
+// GCCallback is invoked before (AddGCPrologueCallback) or after
+// (AddGCEpilogueCallback) each garbage collection pass, so that embedders
+// can correlate collections with request latency or proactively hint V8 to
+// collect during idle periods.
+type GCCallback func(gcType GCType, flags GCCallbackFlags)
+
+// AddGCPrologueCallback registers cb to be invoked immediately before each
+// garbage collection pass on this isolate. Multiple callbacks may be
+// registered; they are invoked in the order added.
+func (i *Isolate) AddGCPrologueCallback(cb GCCallback) {
+	first := len(i.gcPrologueCBs) == 0
+	i.gcPrologueCBs = append(i.gcPrologueCBs, cb)
+	if first {
+		C.IsolateAddGCPrologueCallback(i.ptr)
+	}
+}
+
+// AddGCEpilogueCallback registers cb to be invoked immediately after each
+// garbage collection pass on this isolate. Multiple callbacks may be
+// registered; they are invoked in the order added.
+func (i *Isolate) AddGCEpilogueCallback(cb GCCallback) {
+	first := len(i.gcEpilogueCBs) == 0
+	i.gcEpilogueCBs = append(i.gcEpilogueCBs, cb)
+	if first {
+		C.IsolateAddGCEpilogueCallback(i.ptr)
+	}
+}
+
+// RequestGarbageCollectionForTesting forces a full, synchronous garbage
+// collection on this isolate. As the name suggests, this is only intended
+// for use in tests; production code should rely on V8's own heuristics or,
+// at most, LowMemoryNotification.
+func (i *Isolate) RequestGarbageCollectionForTesting() {
+	C.IsolateRequestGarbageCollectionForTesting(i.ptr)
+}
+
+// LowMemoryNotification hints to V8 that the embedder is under memory
+// pressure, giving it the opportunity to collect garbage more eagerly than
+// it otherwise would, e.g. during an idle period of a long-running server.
+func (i *Isolate) LowMemoryNotification() {
+	C.IsolateLowMemoryNotification(i.ptr)
+}
+
+//export goGCPrologueCallback
+func goGCPrologueCallback(isoPtr C.IsolatePtr, gcType C.int, flags C.int) {
+	v, ok := isolatesByPtr.Load(isoPtr)
+	if !ok {
+		return
+	}
+	iso := v.(*Isolate)
+	for _, cb := range iso.gcPrologueCBs {
+		cb(GCType(gcType), GCCallbackFlags(flags))
+	}
+}
+
+//export goGCEpilogueCallback
+func goGCEpilogueCallback(isoPtr C.IsolatePtr, gcType C.int, flags C.int) {
+	v, ok := isolatesByPtr.Load(isoPtr)
+	if !ok {
+		return
+	}
+	iso := v.(*Isolate)
+	for _, cb := range iso.gcEpilogueCBs {
+		cb(GCType(gcType), GCCallbackFlags(flags))
+	}
+}
@@ -0,0 +1,145 @@
+// Copyright 2019 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8go "rogchap.com/v8go"
+)
+
+func TestIsolateNearHeapLimitCallback(t *testing.T) {
+	t.Parallel()
+
+	iso := v8go.NewIsolateWith(1<<20, 4<<20)
+	defer iso.Dispose()
+
+	var gotCurrent, gotInitial uint64
+	called := make(chan struct{}, 1)
+	iso.SetNearHeapLimitCallback(func(current, initial uint64) uint64 {
+		gotCurrent, gotInitial = current, initial
+		select {
+		case called <- struct{}{}:
+		default:
+		}
+		return current * 2
+	})
+	defer iso.RemoveNearHeapLimitCallback(0)
+
+	ctx := v8go.NewContext(iso)
+	defer ctx.Close()
+
+	// Allocate strings until the heap limit is approached; the callback
+	// should fire and raise the limit before V8 aborts the process.
+	_, err := ctx.RunScript(`
+		let blobs = [];
+		for (let i = 0; i < 10000; i++) {
+			blobs.push(new Array(10000).join("x"));
+		}
+	`, "heap.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	select {
+	case <-called:
+	default:
+		t.Error("expected NearHeapLimitCallback to be invoked")
+	}
+	if gotCurrent == 0 || gotInitial == 0 {
+		t.Errorf("expected non-zero heap limits, got current=%d initial=%d", gotCurrent, gotInitial)
+	}
+}
+
+func TestIsolateSetOOMErrorHandler(t *testing.T) {
+	t.Parallel()
+
+	iso := v8go.NewIsolateWith(1<<20, 2<<20)
+
+	var gotLocation string
+	var gotIsHeapOOM bool
+	called := make(chan struct{}, 1)
+	iso.SetOOMErrorHandler(func(location string, isHeapOOM bool) {
+		gotLocation, gotIsHeapOOM = location, isHeapOOM
+		called <- struct{}{}
+		iso.Dispose()
+	})
+
+	ctx := v8go.NewContext(iso)
+	defer ctx.Close()
+
+	// This will eventually exhaust the tiny heap above; V8 will call our
+	// handler instead of aborting the process.
+	go ctx.RunScript(`
+		let blobs = [];
+		while (true) {
+			blobs.push(new Array(100000).join("x"));
+		}
+	`, "oom.js")
+
+	<-called
+	if gotLocation == "" {
+		t.Error("expected a non-empty failure location")
+	}
+	_ = gotIsHeapOOM
+}
+
+func TestNewIsolateWithOptions(t *testing.T) {
+	t.Parallel()
+
+	iso := v8go.NewIsolateWithOptions(v8go.IsolateOptions{
+		MaxOldSpaceSizeMB:            64,
+		MaxYoungGenerationSizeMB:     16,
+		InitialOldSpaceSizeMB:        4,
+		InitialYoungGenerationSizeMB: 2,
+		CodeRangeSizeMB:              32,
+	})
+	defer iso.Dispose()
+
+	ctx := v8go.NewContext(iso)
+	defer ctx.Close()
+	val, err := ctx.RunScript(`1 + 1`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := val.Integer(); got != 2 {
+		t.Errorf("expected 2, got %d", got)
+	}
+}
+
+func TestIsolateGCCallbacks(t *testing.T) {
+	t.Parallel()
+
+	iso := v8go.NewIsolate()
+	defer iso.Dispose()
+
+	var prologues, epilogues int
+	iso.AddGCPrologueCallback(func(gcType v8go.GCType, flags v8go.GCCallbackFlags) {
+		prologues++
+	})
+	iso.AddGCEpilogueCallback(func(gcType v8go.GCType, flags v8go.GCCallbackFlags) {
+		epilogues++
+	})
+
+	iso.RequestGarbageCollectionForTesting()
+	iso.LowMemoryNotification()
+
+	if prologues == 0 {
+		t.Error("expected at least one GC prologue callback invocation")
+	}
+	if epilogues == 0 {
+		t.Error("expected at least one GC epilogue callback invocation")
+	}
+}
+
+func TestIsolateRemoveNearHeapLimitCallbackWithoutSet(t *testing.T) {
+	t.Parallel()
+
+	iso := v8go.NewIsolate()
+	defer iso.Dispose()
+
+	// Removing a callback that was never installed should be a no-op, not a panic.
+	iso.RemoveNearHeapLimitCallback(0)
+}
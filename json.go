@@ -26,6 +26,63 @@ func JSONParse(ctx *Context, str string) (*Value, error) {
 	return valueResult(ctx, rtn)
 }
 
+// UnmarshalJSON parses data as JSON within ctx and returns the resulting
+// *Value. Like JSONParse, it calls V8's JSON parser directly rather than
+// going through RunScript, so data can contain quotes, backticks, or
+// template-literal syntax without any risk of it being interpreted as
+// script. A malformed payload is returned as a `JSError` carrying the
+// line/column of the first bad byte, the same way RunScript surfaces
+// script syntax errors.
+func (c *Context) UnmarshalJSON(data []byte) (*Value, error) {
+	return JSONParse(c, string(data))
+}
+
+// JSONParseWithReviver parses str as JSON within ctx and then walks the
+// result applying reviver to each key/value pair, the same way the optional
+// second argument to JSON.parse does (e.g. turning ISO date strings back
+// into Date objects). A nil reviver behaves exactly like JSONParse.
+func JSONParseWithReviver(ctx *Context, str string, reviver *Function) (*Value, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	if reviver == nil {
+		return JSONParse(ctx, str)
+	}
+
+	jsonNsVal, err := ctx.Global().Get("JSON")
+	if err != nil {
+		return nil, err
+	}
+	jsonNs, err := jsonNsVal.AsObject()
+	if err != nil {
+		return nil, err
+	}
+	parseVal, err := jsonNs.Get("parse")
+	if err != nil {
+		return nil, err
+	}
+	parse, err := parseVal.AsFunction()
+	if err != nil {
+		return nil, err
+	}
+	strVal, err := ctx.NewValue(str)
+	if err != nil {
+		return nil, err
+	}
+	return parse.Call(Undefined(ctx.iso), strVal, reviver.Value)
+}
+
+// JSONParseBytes is the isolate-scoped counterpart to (*Context).UnmarshalJSON,
+// parsing data within iso's default context. Use this when you only have an
+// Isolate on hand and don't need the resulting Value tied to a specific
+// Context you created yourself.
+func JSONParseBytes(iso *Isolate, data []byte) (*Value, error) {
+	if iso == nil {
+		return nil, errors.New("v8go: Isolate is required")
+	}
+	return iso.internalContext.UnmarshalJSON(data)
+}
+
 // JSONStringify tries to stringify the JSON-serializable object value and returns it as string.
 func JSONStringify(ctx *Context, val Valuer) (string, error) {
 	var v *Value
@@ -51,3 +108,16 @@ func JSONStringify(ctx *Context, val Valuer) (string, error) {
 		return C.GoStringN(s.data, C.int(s.length)), nil
 	}
 }
+
+// JSONStringifyWithOptions stringifies val like JSONStringify, but applies
+// opts' indentation and/or replacer, matching the semantics of the two- and
+// three-argument forms of JSON.stringify.
+func JSONStringifyWithOptions(ctx *Context, val Valuer, opts JSONStringifyOptions) (string, error) {
+	if ctx == nil {
+		return "", errors.New("v8go: Context is required")
+	}
+	if val == nil || val.value() == nil {
+		return "", errors.New("v8go: Value is required")
+	}
+	return jsonStringify(ctx, val, opts)
+}
@@ -0,0 +1,157 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// JSONStringifyOptions controls JSONStringifyWithOptions and JSONEncoder's
+// output formatting.
+type JSONStringifyOptions struct {
+	// Indent, when non-empty, is used as the `space` argument to
+	// JSON.stringify and repeated per nesting level (e.g. "  " for
+	// two-space indentation). Empty means compact output, the same as
+	// JSONStringify.
+	Indent string
+
+	// Replacer, when non-nil, is either a *Function (called for each
+	// key/value pair, the same as JSON.stringify's function replacer) or a
+	// []string (a whitelist of keys to keep, the same as its array
+	// replacer). Any other type is an error.
+	Replacer interface{}
+}
+
+// JSONEncoder writes the JSON encoding of one or more Values to an
+// underlying io.Writer, mirroring encoding/json.Encoder. With no Indent or
+// Replacer set (see JSONStringifyOptions), Encode streams the output via
+// JSONEncode instead of building the whole encoding in memory first.
+type JSONEncoder struct {
+	ctx  *Context
+	w    io.Writer
+	opts JSONStringifyOptions
+}
+
+// NewJSONEncoder returns a JSONEncoder that writes to w, stringifying
+// values within ctx.
+func NewJSONEncoder(ctx *Context, w io.Writer) *JSONEncoder {
+	return &JSONEncoder{ctx: ctx, w: w}
+}
+
+// SetOptions configures how subsequent Encode calls format their output.
+func (e *JSONEncoder) SetOptions(opts JSONStringifyOptions) {
+	e.opts = opts
+}
+
+// Encode writes val's JSON encoding to the underlying writer. With no
+// Indent or Replacer set, it streams the encoding out via JSONEncode
+// without materializing the whole result first. An Indent or Replacer
+// requires going through JS's JSON.stringify (see jsonStringify), which has
+// no streaming form, so those options fall back to building the full
+// string before writing it.
+func (e *JSONEncoder) Encode(val Valuer) error {
+	if e.opts.Indent == "" && e.opts.Replacer == nil {
+		_, err := JSONEncode(e.w, val)
+		return err
+	}
+	str, err := jsonStringify(e.ctx, val, e.opts)
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(e.w, str)
+	return err
+}
+
+// jsonStringify is the shared implementation behind JSONStringifyWithOptions
+// and JSONEncoder: with no indent and no replacer it delegates to the
+// buffer-optimized JSONStringify, and otherwise it goes through JS's
+// JSON.stringify directly since the C++ JSONStringify helper only supports
+// compact output with no replacer.
+func jsonStringify(ctx *Context, val Valuer, opts JSONStringifyOptions) (string, error) {
+	if opts.Indent == "" && opts.Replacer == nil {
+		return JSONStringify(ctx, val)
+	}
+
+	jsonNsVal, err := ctx.Global().Get("JSON")
+	if err != nil {
+		return "", err
+	}
+	jsonNs, err := jsonNsVal.AsObject()
+	if err != nil {
+		return "", err
+	}
+	stringifyVal, err := jsonNs.Get("stringify")
+	if err != nil {
+		return "", err
+	}
+	stringify, err := stringifyVal.AsFunction()
+	if err != nil {
+		return "", err
+	}
+	replacerVal, err := jsonReplacerValue(ctx, opts.Replacer)
+	if err != nil {
+		return "", err
+	}
+	indentVal, err := ctx.NewValue(opts.Indent)
+	if err != nil {
+		return "", err
+	}
+	rtn, err := stringify.Call(Undefined(ctx.iso), val, replacerVal, indentVal)
+	if err != nil {
+		return "", err
+	}
+	if rtn.IsUndefined() {
+		return "", errors.New("v8go: value is not JSON-serializable")
+	}
+	return rtn.String(), nil
+}
+
+// jsonReplacerValue converts a JSONStringifyOptions.Replacer into the JS
+// value JSON.stringify expects as its second argument.
+func jsonReplacerValue(ctx *Context, replacer interface{}) (*Value, error) {
+	switch r := replacer.(type) {
+	case nil:
+		return Null(ctx.iso), nil
+	case *Function:
+		return r.Value, nil
+	case []string:
+		arr := ctx.NewArray(len(r))
+		for i, key := range r {
+			if err := arr.SetIdx(uint32(i), key); err != nil {
+				return nil, err
+			}
+		}
+		return arr.value(), nil
+	default:
+		return nil, fmt.Errorf("v8go: Replacer must be a *Function or []string, got %T", replacer)
+	}
+}
+
+// JSONDecoder reads JSON text from an underlying io.Reader and parses it
+// into a Value, mirroring encoding/json.Decoder. Decode reads via
+// JSONDecode, which scans only as far as the end of one JSON value rather
+// than buffering the whole reader into memory first.
+type JSONDecoder struct {
+	ctx *Context
+	r   io.Reader
+}
+
+// NewJSONDecoder returns a JSONDecoder that reads from r, parsing within
+// ctx.
+func NewJSONDecoder(ctx *Context, r io.Reader) *JSONDecoder {
+	return &JSONDecoder{ctx: ctx, r: r}
+}
+
+// Decode reads a single JSON value from the underlying reader, via
+// JSONDecode, instead of buffering the whole reader into memory first. The
+// reader is read in chunks of up to 32 KiB until the chunk in which an
+// object or array value ends; the bytes of that chunk past the value's end
+// are discarded, so a later Decode call does not see them. A top-level
+// scalar is read until EOF.
+func (d *JSONDecoder) Decode() (*Value, error) {
+	return JSONDecode(d.ctx, d.r)
+}
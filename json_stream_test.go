@@ -0,0 +1,102 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	v8go "rogchap.com/v8go"
+)
+
+func TestJSONEncoder(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({a: 1, b: "two"})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := v8go.NewJSONEncoder(ctx, &buf).Encode(val); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	want := `{"a":1,"b":"two"}`
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestJSONEncoderIndent(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({a: 1})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	enc := v8go.NewJSONEncoder(ctx, &buf)
+	enc.SetOptions(v8go.JSONStringifyOptions{Indent: "  "})
+	if err := enc.Encode(val); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\n  \"a\"") {
+		t.Errorf("expected indented output, got %q", buf.String())
+	}
+}
+
+func TestJSONDecoderIgnoresTrailingData(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	// If Decode buffered the whole reader (as io.ReadAll would), the
+	// trailing non-JSON bytes below would make parsing fail.
+	r := strings.NewReader(`{"a": 1} trailing garbage that should be ignored`)
+	val, err := v8go.NewJSONDecoder(ctx, r).Decode()
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	if a, _ := obj.Get("a"); a.Integer() != 1 {
+		t.Errorf("expected a == 1, got %v", a)
+	}
+}
+
+func TestJSONDecoder(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	r := strings.NewReader(`{"a": 1, "b": [1, 2, 3]}`)
+	val, err := v8go.NewJSONDecoder(ctx, r).Decode()
+	if err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	if a, _ := obj.Get("a"); a.Integer() != 1 {
+		t.Errorf("expected a == 1, got %v", a)
+	}
+}
@@ -31,6 +31,49 @@ func TestJSONParse(t *testing.T) {
 	}
 }
 
+func TestContextUnmarshalJSON(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.UnmarshalJSON([]byte(`{"foo": "bar"}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	if foo, _ := obj.Get("foo"); foo.String() != "bar" {
+		t.Errorf("expected foo %q, got %q", "bar", foo.String())
+	}
+
+	_, err = ctx.UnmarshalJSON([]byte(`{`))
+	if _, ok := err.(*v8go.JSError); !ok {
+		t.Errorf("expected error to be of type JSError, got: %T", err)
+	}
+}
+
+func TestJSONParseBytes(t *testing.T) {
+	t.Parallel()
+
+	if _, err := v8go.JSONParseBytes(nil, []byte("{}")); err == nil {
+		t.Error("expected error but got <nil>")
+	}
+
+	iso := v8go.NewIsolate()
+	defer iso.Dispose()
+	val, err := v8go.JSONParseBytes(iso, []byte(`[1,2,3]`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !val.IsArray() {
+		t.Fatalf("expected an Array value, got %s", val)
+	}
+}
+
 func TestJSONStringify(t *testing.T) {
 	t.Parallel()
 
@@ -42,6 +85,84 @@ func TestJSONStringify(t *testing.T) {
 	}
 }
 
+func TestJSONStringifyWithOptionsKeyReplacer(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({a: 1, b: 2, c: 3})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := v8go.JSONStringifyWithOptions(ctx, val, v8go.JSONStringifyOptions{
+		Replacer: []string{"a", "c"},
+	})
+	if err != nil {
+		t.Fatalf("JSONStringifyWithOptions failed: %v", err)
+	}
+	if want := `{"a":1,"c":3}`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestJSONStringifyWithOptionsFunctionReplacer(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	fnVal, err := ctx.RunScript(`(function(key, value) { return typeof value === "number" ? value * 2 : value; })`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fn, err := fnVal.AsFunction()
+	if err != nil {
+		t.Fatalf("AsFunction failed: %v", err)
+	}
+	val, err := ctx.RunScript(`({a: 1, b: 2})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := v8go.JSONStringifyWithOptions(ctx, val, v8go.JSONStringifyOptions{Replacer: fn})
+	if err != nil {
+		t.Fatalf("JSONStringifyWithOptions failed: %v", err)
+	}
+	if want := `{"a":2,"b":4}`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestJSONParseWithReviver(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	fnVal, err := ctx.RunScript(`(function(key, value) { return typeof value === "number" ? value * 2 : value; })`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	reviver, err := fnVal.AsFunction()
+	if err != nil {
+		t.Fatalf("AsFunction failed: %v", err)
+	}
+	val, err := v8go.JSONParseWithReviver(ctx, `{"a": 1, "b": 2}`, reviver)
+	if err != nil {
+		t.Fatalf("JSONParseWithReviver failed: %v", err)
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	if a, _ := obj.Get("a"); a.Integer() != 2 {
+		t.Errorf("expected a == 2, got %v", a)
+	}
+}
+
 func ExampleJSONParse() {
 	ctx := v8go.NewContext()
 	defer ctx.Isolate().Dispose()
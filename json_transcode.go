@@ -0,0 +1,353 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+/*
+#include <stdlib.h>
+#include "v8go.h"
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+)
+
+// JSONStringifyTo stringifies val the same way JSONStringify does, but
+// writes the encoded bytes directly to w instead of returning a Go string,
+// so the bytes are copied at most once even when V8 has to fall back to a
+// malloc'd buffer for large output.
+func JSONStringifyTo(w io.Writer, val Valuer) error {
+	var v *Value
+	if val != nil {
+		v = val.value()
+	}
+	if v == nil {
+		return errors.New("v8go: Value is required")
+	}
+	// See JSONStringify for why it's safe to use the Isolate's shared buffer.
+	buffer := v.ctx.iso.stringBuffer
+	bufPtr := unsafe.Pointer(&buffer[0])
+
+	s := C.JSONStringify(v.valuePtr(), bufPtr, C.int(len(buffer)))
+	if s.data == nil {
+		return errors.New("v8go could not encode Value to JSON")
+	}
+	if unsafe.Pointer(s.data) == bufPtr {
+		_, err := w.Write(buffer[0:s.length])
+		return err
+	}
+	// Result was too big for buffer, so the C++ code malloc-ed its own.
+	defer C.free(unsafe.Pointer(s.data))
+	cBytes := (*[1 << 30]byte)(unsafe.Pointer(s.data))[:s.length:s.length]
+	_, err := w.Write(cBytes)
+	return err
+}
+
+// JSONEncode writes val's JSON encoding to w, recursing into objects and
+// arrays itself (via GetOwnPropertyNames and indexed Get) and writing each
+// piece as it's produced, rather than building val's entire encoding in
+// memory first the way JSONStringify/JSONStringifyTo do. It returns the
+// number of bytes written. Prefer this over JSONStringifyTo for values whose
+// encoded form is too large to hold twice (once in V8, once for the write).
+func JSONEncode(w io.Writer, val Valuer) (int64, error) {
+	var v *Value
+	if val != nil {
+		v = val.value()
+	}
+	if v == nil {
+		return 0, errors.New("v8go: Value is required")
+	}
+	cw := &countingWriter{w: w}
+	if err := encodeJSONValue(cw, v); err != nil {
+		return cw.n, err
+	}
+	return cw.n, nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) WriteString(s string) error {
+	n, err := io.WriteString(cw.w, s)
+	cw.n += int64(n)
+	return err
+}
+
+// encodeJSONValue writes v's JSON encoding to cw, following the same
+// undefined/function handling as JSON.stringify: such values encode as
+// "null" inside an array and are omitted entirely from an object.
+func encodeJSONValue(cw *countingWriter, v *Value) error {
+	switch {
+	case v.IsUndefined(), v.IsFunction():
+		return cw.WriteString("null")
+	case v.IsNull():
+		return cw.WriteString("null")
+	case v.IsBoolean():
+		if v.Boolean() {
+			return cw.WriteString("true")
+		}
+		return cw.WriteString("false")
+	case v.IsNumber():
+		f := v.Number()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			// JSON.stringify serializes non-finite numbers as null rather
+			// than "NaN"/"Infinity", which wouldn't be valid JSON anyway.
+			return cw.WriteString("null")
+		}
+		return cw.WriteString(formatJSNumber(f))
+	case v.IsBigInt():
+		// JSON.stringify itself throws a TypeError for BigInt values.
+		return errors.New("v8go: cannot JSON-encode a BigInt value")
+	case v.IsString():
+		return encodeJSONString(cw, v.String())
+	case v.IsDate():
+		// JSON.stringify calls Date.prototype.toJSON, which in turn calls
+		// toISOString (or returns null for an invalid date), rather than
+		// serializing the Date as a plain object.
+		f := v.Number()
+		if math.IsNaN(f) {
+			return cw.WriteString("null")
+		}
+		t := v.exportDate().(time.Time)
+		return encodeJSONString(cw, t.Format("2006-01-02T15:04:05.000Z"))
+	case v.IsArray():
+		return encodeJSONArray(cw, v)
+	case v.IsObject():
+		return encodeJSONObject(cw, v)
+	default:
+		return encodeJSONString(cw, v.String())
+	}
+}
+
+// formatJSNumber formats f (which must be finite) the way JS's
+// Number::toString does (ECMA-262 7.1.12.1), rather than Go's %g notation:
+// fixed notation is used for a much wider magnitude range (roughly 1e-6 to
+// 1e21) than strconv.FormatFloat's 'g' verb picks, and the exponential form
+// uses "e+"/"e-" with no leading zero in the exponent. Callers must not
+// pass NaN or ±Inf; JSON.stringify serializes those as null instead.
+func formatJSNumber(f float64) string {
+	if f == 0 {
+		return "0"
+	}
+	neg := f < 0
+	if neg {
+		f = -f
+	}
+
+	// strconv's shortest round-trip 'e' form gives us the same digit string
+	// and decimal exponent that the ECMA algorithm is defined in terms of:
+	// f == 0.d1d2...dk * 10^(exp+1).
+	s := strconv.AppendFloat(nil, f, 'e', -1, 64)
+	mantissa, expPart, _ := strings.Cut(string(s), "e")
+	exp, err := strconv.Atoi(expPart)
+	if err != nil {
+		// Unreachable: strconv always emits a valid "e±dd" exponent.
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	}
+	digits := strings.Replace(mantissa, ".", "", 1)
+	k := len(digits)
+	n := exp + 1
+
+	var out string
+	switch {
+	case k <= n && n <= 21:
+		out = digits + strings.Repeat("0", n-k)
+	case 0 < n && n <= 21:
+		out = digits[:n] + "." + digits[n:]
+	case -6 < n && n <= 0:
+		out = "0." + strings.Repeat("0", -n) + digits
+	default:
+		mant := digits[:1]
+		if k > 1 {
+			mant += "." + digits[1:]
+		}
+		e := n - 1
+		sign := "+"
+		if e < 0 {
+			sign = "-"
+			e = -e
+		}
+		out = mant + "e" + sign + strconv.Itoa(e)
+	}
+	if neg {
+		return "-" + out
+	}
+	return out
+}
+
+func encodeJSONString(cw *countingWriter, s string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	n, err := cw.w.Write(b)
+	cw.n += int64(n)
+	return err
+}
+
+func encodeJSONArray(cw *countingWriter, v *Value) error {
+	obj, err := v.AsObject()
+	if err != nil {
+		return err
+	}
+	arr := &Array{Object: *obj}
+	if err := cw.WriteString("["); err != nil {
+		return err
+	}
+	length := arr.Length()
+	for i := uint32(0); i < length; i++ {
+		if i > 0 {
+			if err := cw.WriteString(","); err != nil {
+				return err
+			}
+		}
+		elem, err := arr.GetIdx(i)
+		if err != nil {
+			return err
+		}
+		if err := encodeJSONValue(cw, elem); err != nil {
+			return err
+		}
+	}
+	return cw.WriteString("]")
+}
+
+func encodeJSONObject(cw *countingWriter, v *Value) error {
+	obj, err := v.AsObject()
+	if err != nil {
+		return err
+	}
+	keys := objectOwnKeys(obj)
+	if err := cw.WriteString("{"); err != nil {
+		return err
+	}
+	wrote := false
+	for _, key := range keys {
+		val, err := obj.Get(key)
+		if err != nil {
+			return err
+		}
+		if val.IsUndefined() || val.IsFunction() {
+			continue
+		}
+		if wrote {
+			if err := cw.WriteString(","); err != nil {
+				return err
+			}
+		}
+		if err := encodeJSONString(cw, key); err != nil {
+			return err
+		}
+		if err := cw.WriteString(":"); err != nil {
+			return err
+		}
+		if err := encodeJSONValue(cw, val); err != nil {
+			return err
+		}
+		wrote = true
+	}
+	return cw.WriteString("}")
+}
+
+// JSONDecode reads a single JSON value from r and parses it within ctx,
+// mirroring JSONParse but reading r in fixed-size chunks instead of
+// requiring the caller to buffer the whole document into a string first. It
+// scans each chunk just far enough to recognize when a complete top-level
+// value has been read (tracking object/array nesting and string escaping),
+// then hands the accumulated bytes to V8's JSON parser in one call.
+func JSONDecode(ctx *Context, r io.Reader) (*Value, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+
+	const chunkSize = 32 * 1024
+	var buf bytes.Buffer
+	chunk := make([]byte, chunkSize)
+	scanner := &jsonValueScanner{}
+
+	for {
+		n, err := r.Read(chunk)
+		if n > 0 {
+			end, complete := scanner.feed(chunk[:n])
+			buf.Write(chunk[:end])
+			if complete {
+				break
+			}
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return JSONParse(ctx, buf.String())
+}
+
+// jsonValueScanner tracks just enough JSON lexical state (string/escape
+// status and object/array nesting depth) to recognize the end of a single
+// top-level JSON value as it's fed successive chunks of input.
+type jsonValueScanner struct {
+	started  bool
+	scalar   bool
+	inString bool
+	escaped  bool
+	depth    int
+}
+
+// feed scans the next chunk of input and, if a complete top-level value is
+// found partway through it, reports the index just past its last byte and
+// true. Scalars (numbers, strings, booleans, null) have no V8-visible
+// terminator short of EOF, so feed never reports completion for them; the
+// caller is expected to keep reading until EOF.
+func (s *jsonValueScanner) feed(b []byte) (end int, complete bool) {
+	for i, c := range b {
+		if !s.started {
+			switch c {
+			case ' ', '\t', '\n', '\r':
+				continue
+			}
+			s.started = true
+			if c != '{' && c != '[' {
+				s.scalar = true
+			}
+		}
+		if s.scalar {
+			continue
+		}
+		if s.inString {
+			switch {
+			case s.escaped:
+				s.escaped = false
+			case c == '\\':
+				s.escaped = true
+			case c == '"':
+				s.inString = false
+			}
+			continue
+		}
+		switch c {
+		case '"':
+			s.inString = true
+		case '{', '[':
+			s.depth++
+		case '}', ']':
+			s.depth--
+			if s.depth == 0 {
+				return i + 1, true
+			}
+		}
+	}
+	return len(b), false
+}
@@ -0,0 +1,140 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	v8go "rogchap.com/v8go"
+)
+
+func TestJSONStringifyTo(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({a: 1, b: "two"})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := v8go.JSONStringifyTo(&buf, val); err != nil {
+		t.Fatalf("JSONStringifyTo failed: %v", err)
+	}
+	if want := `{"a":1,"b":"two"}`; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestJSONEncode(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({a: 1, b: [true, null, "x"], c: undefined})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	n, err := v8go.JSONEncode(&buf, val)
+	if err != nil {
+		t.Fatalf("JSONEncode failed: %v", err)
+	}
+	want := `{"a":1,"b":[true,null,"x"]}`
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("expected %d bytes written, got %d", len(want), n)
+	}
+}
+
+func TestJSONEncodeDate(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({when: new Date(Date.UTC(2020, 0, 2, 3, 4, 5, 6))})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var buf bytes.Buffer
+	if _, err := v8go.JSONEncode(&buf, val); err != nil {
+		t.Fatalf("JSONEncode failed: %v", err)
+	}
+	want := `{"when":"2020-01-02T03:04:05.006Z"}`
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestJSONEncodeNumberFormatting(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	cases := []struct {
+		src  string
+		want string
+	}{
+		{"0", "0"},
+		{"-0", "0"},
+		{"1", "1"},
+		{"-1.5", "-1.5"},
+		{"100", "100"},
+		{"1e21", "1e+21"},
+		{"1e20", "100000000000000000000"},
+		{"1e-6", "0.000001"},
+		{"1e-7", "1e-7"},
+		{"123456789012345680000", "123456789012345680000"},
+		{"NaN", "null"},
+		{"Infinity", "null"},
+		{"-Infinity", "null"},
+	}
+	for _, c := range cases {
+		val, err := ctx.RunScript(c.src, "main.js")
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", c.src, err)
+		}
+		var buf bytes.Buffer
+		if _, err := v8go.JSONEncode(&buf, val); err != nil {
+			t.Fatalf("JSONEncode failed for %q: %v", c.src, err)
+		}
+		if buf.String() != c.want {
+			t.Errorf("%s: got %q, want %q", c.src, buf.String(), c.want)
+		}
+	}
+}
+
+func TestJSONDecode(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	r := strings.NewReader(`{"a": 1, "b": [1, 2, 3]}  trailing garbage that should be ignored`)
+	val, err := v8go.JSONDecode(ctx, r)
+	if err != nil {
+		t.Fatalf("JSONDecode failed: %v", err)
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	if a, _ := obj.Get("a"); a.Integer() != 1 {
+		t.Errorf("expected a == 1, got %v", a)
+	}
+}
@@ -0,0 +1,210 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+// #include "v8go.h"
+import "C"
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// newValueFromReflect extends Context.NewValue to composite Go types that
+// don't fit the fixed scalar switch: slices/arrays become JS Arrays, maps
+// with string or integer keys become plain Objects, structs become Objects
+// honoring `json` tags (name, "-", omitempty, embedding), []byte becomes a
+// Uint8Array backed by a copy of the same bytes, time.Time becomes a JS
+// Date, and func(...) values become JS Functions bound to a FunctionTemplate
+// that marshals arguments/return value through ExportTo/NewValue.
+//
+// seen tracks Go pointers already being converted, so a cyclic Go value
+// (e.g. a struct containing a pointer to itself) is rejected with a clear
+// error instead of overflowing the stack.
+func (c *Context) newValueFromReflect(rv reflect.Value, seen map[uintptr]bool) (*Value, error) {
+	switch rv.Kind() {
+	case reflect.Invalid:
+		return c.iso.null, nil
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return c.iso.null, nil
+		}
+		ptr := rv.Pointer()
+		if seen[ptr] {
+			return nil, fmt.Errorf("v8go: NewValue cannot convert a cyclic Go value (%s)", rv.Type())
+		}
+		seen[ptr] = true
+		defer delete(seen, ptr)
+		return c.newValueFromReflect(rv.Elem(), seen)
+	case reflect.Interface:
+		if rv.IsNil() {
+			return c.iso.null, nil
+		}
+		return c.newValueFromReflect(rv.Elem(), seen)
+	}
+
+	if rv.Type() == reflect.TypeOf(time.Time{}) {
+		return c.newDateValue(rv.Interface().(time.Time))
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			b := make([]byte, rv.Len())
+			reflect.Copy(reflect.ValueOf(b), rv)
+			return c.NewUint8Array(b)
+		}
+		return c.newArrayValueFromReflect(rv, seen)
+	case reflect.Map:
+		return c.newObjectValueFromMap(rv, seen)
+	case reflect.Struct:
+		return c.newObjectValueFromStruct(rv, seen)
+	case reflect.Func:
+		return c.newFunctionValueFromFunc(rv)
+	case reflect.Bool:
+		return c.NewValue(rv.Bool())
+	case reflect.String:
+		return c.NewValue(rv.String())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return c.NewValue(rv.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return c.NewValue(rv.Uint())
+	case reflect.Float32, reflect.Float64:
+		return c.NewValue(rv.Float())
+	default:
+		return nil, fmt.Errorf("v8go: unsupported value type `%s`", rv.Type())
+	}
+}
+
+func (c *Context) newArrayValueFromReflect(rv reflect.Value, seen map[uintptr]bool) (*Value, error) {
+	length := rv.Len()
+	arr := c.NewArray(length)
+	for i := 0; i < length; i++ {
+		elemVal, err := c.newValueFromReflect(rv.Index(i), seen)
+		if err != nil {
+			return nil, err
+		}
+		if err := arr.SetIdx(uint32(i), elemVal); err != nil {
+			return nil, err
+		}
+	}
+	return arr.Value, nil
+}
+
+func (c *Context) newObjectValueFromMap(rv reflect.Value, seen map[uintptr]bool) (*Value, error) {
+	switch rv.Type().Key().Kind() {
+	case reflect.String, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+	default:
+		return nil, fmt.Errorf("v8go: unsupported map key type `%s`", rv.Type().Key())
+	}
+
+	obj := c.NewObject()
+	iter := rv.MapRange()
+	for iter.Next() {
+		elemVal, err := c.newValueFromReflect(iter.Value(), seen)
+		if err != nil {
+			return nil, err
+		}
+		key := fmt.Sprintf("%v", iter.Key().Interface())
+		if err := obj.Set(key, elemVal); err != nil {
+			return nil, err
+		}
+	}
+	return obj.Value, nil
+}
+
+func (c *Context) newObjectValueFromStruct(rv reflect.Value, seen map[uintptr]bool) (*Value, error) {
+	obj := c.NewObject()
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" && !field.Anonymous {
+			continue // unexported
+		}
+		tag := field.Tag.Get("json")
+		name, omitempty := parseJSONTag(tag, field.Name)
+		if name == "-" {
+			continue
+		}
+		fv := rv.Field(i)
+		if omitempty && isEmptyValue(fv) {
+			continue
+		}
+		elemVal, err := c.newValueFromReflect(fv, seen)
+		if err != nil {
+			return nil, err
+		}
+		if err := obj.Set(name, elemVal); err != nil {
+			return nil, err
+		}
+	}
+	return obj.Value, nil
+}
+
+// newFunctionValueFromFunc wraps a Go func value as a JS Function: calling
+// it from JS converts each JS argument into the corresponding Go parameter
+// type via Value.ExportTo, invokes the Go func, and converts a single
+// return value back with Context.NewValue. Mismatched argument/return types
+// surface as a thrown JS exception rather than a Go panic.
+func (c *Context) newFunctionValueFromFunc(rv reflect.Value) (*Value, error) {
+	t := rv.Type()
+	tmpl := NewFunctionTemplate(c.iso, func(info *FunctionCallbackInfo) *Value {
+		ctx := info.Context()
+		args := info.Args()
+		in := make([]reflect.Value, t.NumIn())
+		for i := range in {
+			in[i] = reflect.New(t.In(i)).Elem()
+			if i < len(args) {
+				if err := args[i].assignTo(in[i]); err != nil {
+					return ctx.Isolate().ThrowException(throwableValue(ctx, err))
+				}
+			}
+		}
+		out := rv.Call(in)
+		if len(out) == 0 {
+			return Undefined(ctx.iso)
+		}
+		rtnVal, err := ctx.NewValue(out[0].Interface())
+		if err != nil {
+			return ctx.Isolate().ThrowException(throwableValue(ctx, err))
+		}
+		return rtnVal
+	})
+	return tmpl.GetFunction(c).Value, nil
+}
+
+// throwableValue converts a Go error into a *Value suitable for
+// Isolate.ThrowException. Errors can always be represented as a JS string,
+// so the conversion itself cannot fail.
+func throwableValue(ctx *Context, err error) *Value {
+	v, _ := ctx.NewValue(err.Error())
+	return v
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+// newDateValue creates a JS Date value from a Go time.Time.
+func (c *Context) newDateValue(t time.Time) (*Value, error) {
+	epochMillis := float64(t.UnixNano()) / 1e6
+	ptr := C.NewValueDate(c.ptr, C.double(epochMillis))
+	return &Value{ptr: ptr, ctx: c}, nil
+}
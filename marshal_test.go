@@ -0,0 +1,131 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+	"time"
+
+	v8go "rogchap.com/v8go"
+)
+
+type address struct {
+	City    string `json:"city"`
+	Zip     string `json:"zip,omitempty"`
+	private string //nolint:unused,structcheck
+}
+
+type contact struct {
+	Name      string    `json:"name"`
+	Age       int       `json:"age"`
+	Addresses []address `json:"addresses"`
+	Created   time.Time `json:"created"`
+	Tags      []byte    `json:"-"`
+}
+
+func TestNewValueComposite(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	c := contact{
+		Name: "Ada",
+		Age:  36,
+		Addresses: []address{
+			{City: "London", Zip: "SW1"},
+		},
+		Created: time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tags:    []byte("ignored"),
+	}
+
+	val, err := ctx.NewValue(c)
+	if err != nil {
+		t.Fatalf("NewValue failed: %v", err)
+	}
+
+	obj, err := val.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	if name, _ := obj.Get("name"); name.String() != "Ada" {
+		t.Errorf("expected name %q, got %q", "Ada", name.String())
+	}
+	if obj.Has("Tags") || obj.Has("-") {
+		t.Error("expected json:\"-\" field to be omitted")
+	}
+}
+
+func TestNewValueFunc(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	add := func(a, b int) int { return a + b }
+	val, err := ctx.NewValue(add)
+	if err != nil {
+		t.Fatalf("NewValue failed: %v", err)
+	}
+	if !val.IsFunction() {
+		t.Fatalf("expected a Function value, got %s", val)
+	}
+
+	global := ctx.Global()
+	if err := global.Set("add", val); err != nil {
+		t.Fatalf("Set failed: %v", err)
+	}
+	rtn, err := ctx.RunScript(`add(1, 2)`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rtn.Integer() != 3 {
+		t.Errorf("expected 3, got %d", rtn.Integer())
+	}
+}
+
+func TestNewValueCyclicPointer(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	type node struct {
+		Next *node
+	}
+	n := &node{}
+	n.Next = n
+
+	if _, err := ctx.NewValue(n); err == nil {
+		t.Error("expected error for cyclic Go value, got <nil>")
+	}
+}
+
+func BenchmarkGoToV8Struct(b *testing.B) {
+	iso := v8go.NewIsolate()
+	defer iso.Dispose()
+	ctx := v8go.NewContext(iso)
+	defer ctx.Close()
+
+	c := contact{
+		Name: "Ada",
+		Age:  36,
+		Addresses: []address{
+			{City: "London", Zip: "SW1"},
+			{City: "Paris", Zip: "75001"},
+		},
+		Created: time.Now(),
+	}
+
+	b.ResetTimer()
+	for i := b.N; i > 0; i-- {
+		if _, err := ctx.NewValue(c); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
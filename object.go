@@ -24,6 +24,17 @@ type Object struct {
 	*Value
 }
 
+// NewObject creates a new, empty JS Object associated with the Isolate's
+// internal Context.
+func NewObject(iso *Isolate) *Object {
+	return iso.internalContext.NewObject()
+}
+
+// NewObject creates a new, empty JS Object.
+func (c *Context) NewObject() *Object {
+	return &Object{&Value{ctx: c, ptr: C.NewObject(c.ptr)}}
+}
+
 func (o *Object) MethodCall(methodName string, args ...Valuer) (*Value, error) {
 	getRtn := C.ObjectGetGo(o.valuePtr(), methodName)
 	prop, err := valueResult(o.ctx, getRtn)
@@ -0,0 +1,118 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+/*
+#include <stdlib.h>
+#include "v8go.h"
+static int ObjectHasOwnPropertyGo(ValuePtr ptr, _GoString_ key) {
+		return ObjectHasOwnProperty(ptr, _GoStringPtr(key), _GoStringLen(key)); }
+static RtnPropertyDescriptor ObjectGetOwnPropertyDescriptorGo(ValuePtr ptr, _GoString_ key) {
+		return ObjectGetOwnPropertyDescriptor(ptr, _GoStringPtr(key), _GoStringLen(key)); }
+static int ObjectDefinePropertyGo(ValuePtr ptr, _GoString_ key, ValuePtr value, ValuePtr get, ValuePtr set,
+		int writable, int enumerable, int configurable) {
+		return ObjectDefineProperty(ptr, _GoStringPtr(key), _GoStringLen(key), value, get, set, writable, enumerable, configurable); }
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// GetOwnPropertyNames returns the object's own enumerable string-keyed
+// property names, in insertion order, not including any inherited from the
+// prototype chain. Use GetPropertyNames to include those too.
+func (o *Object) GetOwnPropertyNames() []string {
+	return objectOwnKeys(o)
+}
+
+// GetPropertyNames returns the object's enumerable string-keyed property
+// names, including those inherited from the prototype chain.
+func (o *Object) GetPropertyNames() []string {
+	rtn := C.ObjectGetPropertyNames(o.valuePtr())
+	if rtn.count == 0 {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(rtn.names))
+	cNames := (*[1 << 20]*C.char)(unsafe.Pointer(rtn.names))[:rtn.count:rtn.count]
+	names := make([]string, rtn.count)
+	for i, cName := range cNames {
+		names[i] = C.GoString(cName)
+		C.free(unsafe.Pointer(cName))
+	}
+	return names
+}
+
+// HasOwnProperty reports whether the object has key as an own property,
+// i.e. one not inherited from the prototype chain.
+func (o *Object) HasOwnProperty(key string) bool {
+	return C.ObjectHasOwnPropertyGo(o.valuePtr(), key) != 0
+}
+
+// PropertyDescriptor describes a single property of an Object, as accepted
+// by DefineProperty and returned by GetOwnPropertyDescriptor. A data
+// property sets Value (and leaves Get/Set nil); an accessor property sets
+// Get and/or Set (and leaves Value nil).
+type PropertyDescriptor struct {
+	Value                              *Value
+	Get, Set                           *Function
+	Writable, Enumerable, Configurable bool
+}
+
+func boolToCInt(b bool) C.int {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// DefineProperty defines or reconfigures the property named key on the
+// object according to desc, the same way Object.defineProperty does in JS.
+func (o *Object) DefineProperty(key string, desc PropertyDescriptor) error {
+	var valPtr, getPtr, setPtr C.ValuePtr
+	if desc.Value != nil {
+		valPtr = desc.Value.valuePtr()
+	}
+	if desc.Get != nil {
+		getPtr = desc.Get.valuePtr()
+	}
+	if desc.Set != nil {
+		setPtr = desc.Set.valuePtr()
+	}
+	ok := C.ObjectDefinePropertyGo(o.valuePtr(), key, valPtr, getPtr, setPtr,
+		boolToCInt(desc.Writable), boolToCInt(desc.Enumerable), boolToCInt(desc.Configurable))
+	if ok == 0 {
+		return fmt.Errorf("v8go: failed to define property %q", key)
+	}
+	return nil
+}
+
+// GetOwnPropertyDescriptor returns the descriptor for the object's own
+// property named key, or (nil, nil) if the object has no own property by
+// that name.
+func (o *Object) GetOwnPropertyDescriptor(key string) (*PropertyDescriptor, error) {
+	rtn := C.ObjectGetOwnPropertyDescriptorGo(o.valuePtr(), key)
+	if rtn.error.msg != nil {
+		return nil, newJSError(rtn.error)
+	}
+	if rtn.found == 0 {
+		return nil, nil
+	}
+	desc := &PropertyDescriptor{
+		Writable:     rtn.writable != 0,
+		Enumerable:   rtn.enumerable != 0,
+		Configurable: rtn.configurable != 0,
+	}
+	if rtn.value != nil {
+		desc.Value = &Value{ptr: rtn.value, ctx: o.ctx}
+	}
+	if rtn.get != nil {
+		desc.Get = &Function{&Value{ptr: rtn.get, ctx: o.ctx}}
+	}
+	if rtn.set != nil {
+		desc.Set = &Function{&Value{ptr: rtn.set, ctx: o.ctx}}
+	}
+	return desc, nil
+}
@@ -0,0 +1,133 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8go "rogchap.com/v8go"
+)
+
+func TestObjectGetOwnPropertyNames(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({a: 1, b: 2})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	names := obj.GetOwnPropertyNames()
+	if len(names) != 2 || names[0] != "a" || names[1] != "b" {
+		t.Errorf("expected [a b], got %v", names)
+	}
+}
+
+func TestObjectGetPropertyNamesIncludesInherited(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`Object.create({inherited: 1}, {own: {value: 2, enumerable: true}})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	if obj.HasOwnProperty("inherited") {
+		t.Error("expected inherited not to be an own property")
+	}
+	if !obj.HasOwnProperty("own") {
+		t.Error("expected own to be an own property")
+	}
+
+	names := obj.GetPropertyNames()
+	var sawOwn, sawInherited bool
+	for _, name := range names {
+		switch name {
+		case "own":
+			sawOwn = true
+		case "inherited":
+			sawInherited = true
+		}
+	}
+	if !sawOwn || !sawInherited {
+		t.Errorf("expected own and inherited in %v", names)
+	}
+}
+
+func TestObjectDefineAndGetOwnPropertyDescriptor(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	obj, err := val.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+
+	hidden, err := ctx.NewValue("secret")
+	if err != nil {
+		t.Fatalf("NewValue failed: %v", err)
+	}
+	if err := obj.DefineProperty("hidden", v8go.PropertyDescriptor{
+		Value:      hidden,
+		Enumerable: false,
+		Writable:   true,
+	}); err != nil {
+		t.Fatalf("DefineProperty failed: %v", err)
+	}
+
+	if !obj.HasOwnProperty("hidden") {
+		t.Error("expected hidden to be an own property, regardless of enumerability")
+	}
+	names := obj.GetOwnPropertyNames()
+	for _, name := range names {
+		if name == "hidden" {
+			t.Errorf("expected non-enumerable hidden to be excluded from %v", names)
+		}
+	}
+
+	desc, err := obj.GetOwnPropertyDescriptor("hidden")
+	if err != nil {
+		t.Fatalf("GetOwnPropertyDescriptor failed: %v", err)
+	}
+	if desc == nil {
+		t.Fatal("expected a descriptor, got nil")
+	}
+	if got := desc.Value.String(); got != "secret" {
+		t.Errorf("expected value secret, got %q", got)
+	}
+	if desc.Enumerable {
+		t.Error("expected Enumerable to be false")
+	}
+	if !desc.Writable {
+		t.Error("expected Writable to be true")
+	}
+
+	missing, err := obj.GetOwnPropertyDescriptor("nope")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if missing != nil {
+		t.Errorf("expected nil descriptor for missing property, got %+v", missing)
+	}
+}
@@ -0,0 +1,233 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+/*
+#include <stdlib.h>
+#include "v8go.h"
+*/
+import "C"
+import (
+	"errors"
+	"sync"
+	"unsafe"
+)
+
+// Serialize encodes this value into V8's structured-clone wire format, the
+// same binary encoding used internally by postMessage and IndexedDB. Unlike
+// MarshalJSON, it round-trips Map, Set, Date, RegExp, ArrayBuffer/TypedArray
+// values, BigInt, and cyclic object graphs. Pair with Deserialize to
+// reconstruct the value, possibly in a different Context.
+func (v *Value) Serialize() ([]byte, error) {
+	s := NewSerializer(v.ctx, nil)
+	if err := s.WriteValue(v); err != nil {
+		s.release()
+		return nil, err
+	}
+	return s.Bytes(), nil
+}
+
+// Deserialize reconstructs a value previously produced by Value.Serialize,
+// within ctx.
+func Deserialize(ctx *Context, data []byte) (*Value, error) {
+	if ctx == nil {
+		return nil, errors.New("v8go: Context is required")
+	}
+	d := NewDeserializer(ctx, data, nil)
+	defer d.release()
+	return d.ReadValue()
+}
+
+// SerializerDelegate lets a Serializer handle values V8 cannot encode on
+// its own: objects backed by Go state rather than plain JS data.
+type SerializerDelegate interface {
+	// WriteHostObject is called for a JS object V8 doesn't recognize as one
+	// of its own kinds (e.g. one created by a Go-backed FunctionTemplate).
+	// Implementations encode obj into the stream via Serializer.WriteRawBytes.
+	WriteHostObject(s *Serializer, obj *Object) error
+}
+
+// Serializer writes one or more Values to V8's structured-clone wire
+// format, optionally delegating host objects to a SerializerDelegate and
+// large ArrayBuffers to out-of-band transfer via TransferArrayBuffer.
+type Serializer struct {
+	ctx      *Context
+	ptr      C.SerializerPtr
+	delegate SerializerDelegate
+	released bool
+}
+
+var serializersByPtr sync.Map // C.SerializerPtr -> *Serializer
+
+// NewSerializer creates a Serializer bound to ctx. delegate may be nil if
+// the values being written never contain host objects.
+func NewSerializer(ctx *Context, delegate SerializerDelegate) *Serializer {
+	s := &Serializer{ctx: ctx, delegate: delegate}
+	s.ptr = C.NewValueSerializer(ctx.ptr)
+	serializersByPtr.Store(s.ptr, s)
+	return s
+}
+
+// WriteValue appends v's structured-clone encoding to the serializer's
+// internal buffer.
+func (s *Serializer) WriteValue(v *Value) error {
+	rtn := C.ValueSerializerWriteValue(s.ptr, v.valuePtr())
+	if rtn.data != nil {
+		defer C.free(unsafe.Pointer(rtn.data))
+		return errors.New(C.GoStringN(rtn.data, C.int(rtn.length)))
+	}
+	return nil
+}
+
+// WriteRawBytes appends b verbatim to the serializer's buffer. It is only
+// meaningful from within SerializerDelegate.WriteHostObject, to record a
+// host object's own encoding alongside the values V8 understands natively.
+func (s *Serializer) WriteRawBytes(b []byte) {
+	if len(b) == 0 {
+		return
+	}
+	C.ValueSerializerWriteRawBytes(s.ptr, unsafe.Pointer(&b[0]), C.int(len(b)))
+}
+
+// TransferArrayBuffer registers buf, which must be an ArrayBuffer, to be
+// transferred out-of-band under id: its bytes are omitted from the written
+// stream, and the corresponding Deserializer.TransferArrayBuffer call
+// supplies them back on the read side. This is how postMessage moves large
+// buffers between realms without copying them into the wire format.
+func (s *Serializer) TransferArrayBuffer(id uint32, buf *Value) error {
+	if !buf.IsArrayBuffer() {
+		return errors.New("v8go: TransferArrayBuffer requires an ArrayBuffer value")
+	}
+	C.ValueSerializerTransferArrayBuffer(s.ptr, C.uint32_t(id), buf.valuePtr())
+	return nil
+}
+
+// Bytes releases the serializer and returns its accumulated buffer. The
+// Serializer must not be used again afterwards.
+func (s *Serializer) Bytes() []byte {
+	rtn := C.ValueSerializerRelease(s.ptr)
+	s.release()
+	if rtn.data == nil {
+		return nil
+	}
+	defer C.free(unsafe.Pointer(rtn.data))
+	return C.GoBytes(unsafe.Pointer(rtn.data), C.int(rtn.length))
+}
+
+func (s *Serializer) release() {
+	if s.released {
+		return
+	}
+	s.released = true
+	serializersByPtr.Delete(s.ptr)
+}
+
+//export goWriteHostObject
+func goWriteHostObject(ptr C.SerializerPtr, objPtr C.ValuePtr) C.int {
+	v, ok := serializersByPtr.Load(ptr)
+	if !ok {
+		return 0
+	}
+	s := v.(*Serializer)
+	if s.delegate == nil {
+		return 0
+	}
+	obj, err := (&Value{ptr: objPtr, ctx: s.ctx}).AsObject()
+	if err != nil {
+		return 0
+	}
+	if err := s.delegate.WriteHostObject(s, obj); err != nil {
+		return 0
+	}
+	return 1
+}
+
+// DeserializerDelegate is the read-side counterpart to SerializerDelegate.
+type DeserializerDelegate interface {
+	// ReadHostObject reconstructs a host object previously written by
+	// SerializerDelegate.WriteHostObject, reading its encoding back via
+	// Deserializer.ReadRawBytes.
+	ReadHostObject(d *Deserializer) (*Object, error)
+}
+
+// Deserializer reads Values previously written by a Serializer back out of
+// V8's structured-clone wire format.
+type Deserializer struct {
+	ctx      *Context
+	ptr      C.DeserializerPtr
+	data     []byte // keeps data alive for as long as ptr references it
+	delegate DeserializerDelegate
+	released bool
+}
+
+var deserializersByPtr sync.Map // C.DeserializerPtr -> *Deserializer
+
+// NewDeserializer creates a Deserializer over data, bound to ctx. delegate
+// may be nil if the stream never contains host objects.
+func NewDeserializer(ctx *Context, data []byte, delegate DeserializerDelegate) *Deserializer {
+	d := &Deserializer{ctx: ctx, data: data, delegate: delegate}
+	var dataPtr *C.char
+	if len(data) > 0 {
+		dataPtr = (*C.char)(unsafe.Pointer(&data[0]))
+	}
+	d.ptr = C.NewValueDeserializer(ctx.ptr, dataPtr, C.int(len(data)))
+	deserializersByPtr.Store(d.ptr, d)
+	return d
+}
+
+// ReadValue decodes the next Value from the stream.
+func (d *Deserializer) ReadValue() (*Value, error) {
+	rtn := C.ValueDeserializerReadValue(d.ptr)
+	return valueResult(d.ctx, rtn)
+}
+
+// ReadRawBytes reads n raw bytes from the stream. It is only meaningful
+// from within DeserializerDelegate.ReadHostObject, to read back a host
+// object's own encoding.
+func (d *Deserializer) ReadRawBytes(n int) []byte {
+	buf := make([]byte, n)
+	if n == 0 {
+		return buf
+	}
+	C.ValueDeserializerReadRawBytes(d.ptr, unsafe.Pointer(&buf[0]), C.int(n))
+	return buf
+}
+
+// TransferArrayBuffer supplies the bytes for an ArrayBuffer that was
+// registered on the write side via Serializer.TransferArrayBuffer under the
+// same id.
+func (d *Deserializer) TransferArrayBuffer(id uint32, buf *Value) error {
+	if !buf.IsArrayBuffer() {
+		return errors.New("v8go: TransferArrayBuffer requires an ArrayBuffer value")
+	}
+	C.ValueDeserializerTransferArrayBuffer(d.ptr, C.uint32_t(id), buf.valuePtr())
+	return nil
+}
+
+func (d *Deserializer) release() {
+	if d.released {
+		return
+	}
+	d.released = true
+	C.ValueDeserializerRelease(d.ptr)
+	deserializersByPtr.Delete(d.ptr)
+}
+
+//export goReadHostObject
+func goReadHostObject(ptr C.DeserializerPtr) C.ValuePtr {
+	v, ok := deserializersByPtr.Load(ptr)
+	if !ok {
+		return nil
+	}
+	d := v.(*Deserializer)
+	if d.delegate == nil {
+		return nil
+	}
+	obj, err := d.delegate.ReadHostObject(d)
+	if err != nil {
+		return nil
+	}
+	return obj.valuePtr()
+}
@@ -0,0 +1,80 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8go "rogchap.com/v8go"
+)
+
+func TestValueSerializeRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`({a: 1, b: [1, 2, 3], c: new Date(0)})`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := val.Serialize()
+	if err != nil {
+		t.Fatalf("Serialize failed: %v", err)
+	}
+	if len(data) == 0 {
+		t.Fatal("expected a non-empty serialized buffer")
+	}
+
+	rtn, err := v8go.Deserialize(ctx, data)
+	if err != nil {
+		t.Fatalf("Deserialize failed: %v", err)
+	}
+	obj, err := rtn.AsObject()
+	if err != nil {
+		t.Fatalf("AsObject failed: %v", err)
+	}
+	if a, _ := obj.Get("a"); a.Integer() != 1 {
+		t.Errorf("expected a == 1, got %v", a)
+	}
+}
+
+func TestDeserializeRequiresContext(t *testing.T) {
+	t.Parallel()
+
+	if _, err := v8go.Deserialize(nil, []byte{}); err == nil {
+		t.Error("expected error but got <nil>")
+	}
+}
+
+func TestSerializerWriteMultipleValues(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	one, err := ctx.NewValue(int32(1))
+	if err != nil {
+		t.Fatalf("NewValue failed: %v", err)
+	}
+	two, err := ctx.NewValue(int32(2))
+	if err != nil {
+		t.Fatalf("NewValue failed: %v", err)
+	}
+
+	s := v8go.NewSerializer(ctx, nil)
+	if err := s.WriteValue(one); err != nil {
+		t.Fatalf("WriteValue failed: %v", err)
+	}
+	if err := s.WriteValue(two); err != nil {
+		t.Fatalf("WriteValue failed: %v", err)
+	}
+	if len(s.Bytes()) == 0 {
+		t.Fatal("expected a non-empty serialized buffer")
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+/*
+#include <stdlib.h>
+#include "v8go.h"
+static RtnSnapshot CreateSnapshotGo(_GoString_ src, _GoString_ org) {
+	return CreateSnapshot(_GoStringPtr(src), _GoStringLen(src),
+						_GoStringPtr(org), _GoStringLen(org)); }
+*/
+import "C"
+import "unsafe"
+
+// Snapshot is a serialized V8 heap, produced by CreateSnapshot, that can be
+// used to boot new isolates pre-populated with whatever globals and
+// functions the snapshot's setup script left behind. Passing one to
+// NewIsolateFromSnapshot lets a server amortize expensive framework
+// initialization (e.g. loading a bundle of polyfills) across many
+// short-lived isolates instead of re-running the setup script in each one.
+type Snapshot struct {
+	data []byte
+}
+
+// CreateSnapshot runs source (using origin as the script name for stack
+// traces) to completion in a fresh, throwaway isolate, then serializes the
+// resulting heap into a Snapshot. error will be of type `JSError` if source
+// fails to compile or run.
+//
+// The isolate used to create the snapshot cannot register Go function
+// templates, so source may only rely on builtins and values it creates
+// itself; NewIsolateFromSnapshot is the place to attach Go functions once
+// the snapshot has been restored into a real isolate.
+func CreateSnapshot(source, origin string) (*Snapshot, error) {
+	rtn := C.CreateSnapshotGo(source, origin)
+	if rtn.data == nil {
+		return nil, newJSError(rtn.error)
+	}
+	defer C.free(unsafe.Pointer(rtn.data))
+	return &Snapshot{data: C.GoBytes(unsafe.Pointer(rtn.data), rtn.length)}, nil
+}
+
+// Bytes returns the serialized form of the Snapshot, suitable for writing to
+// disk or across a wire and later passed back to NewIsolateFromSnapshot.
+func (s *Snapshot) Bytes() []byte {
+	return s.data
+}
+
+// IsolateOptions carries the configuration used to construct a new Isolate.
+// The zero value selects V8's defaults throughout. See NewIsolateWithOptions
+// for the full set of V8 ResourceConstraints it exposes.
+type IsolateOptions struct {
+	// InitialHeapSize and MaxHeapSize, in bytes, bound the isolate's heap as
+	// with NewIsolateWith. Zero selects V8's default autosizing heuristics.
+	InitialHeapSize uint64
+	MaxHeapSize     uint64
+
+	// MaxOldSpaceSizeMB caps the old generation heap, in megabytes.
+	MaxOldSpaceSizeMB uint64
+	// MaxYoungGenerationSizeMB caps the young generation (nursery) heap, in
+	// megabytes.
+	MaxYoungGenerationSizeMB uint64
+	// InitialOldSpaceSizeMB sets the old generation heap's starting size, in
+	// megabytes, instead of letting V8 grow it from nothing.
+	InitialOldSpaceSizeMB uint64
+	// InitialYoungGenerationSizeMB sets the young generation heap's starting
+	// size, in megabytes.
+	InitialYoungGenerationSizeMB uint64
+	// CodeRangeSizeMB caps the memory range V8 reserves for generated code,
+	// in megabytes.
+	CodeRangeSizeMB uint64
+	// StackLimit sets the address, if non-zero, that V8 treats as the limit
+	// of the native stack available to JS execution.
+	StackLimit uint64
+}
+
+// NewIsolateFromSnapshot creates a new Isolate whose default Context starts
+// pre-populated with the heap captured by data (as produced by
+// CreateSnapshot), rather than the empty state NewIsolate would otherwise
+// produce. Of opts, only InitialHeapSize and MaxHeapSize are applied; the
+// other IsolateOptions fields are ignored.
+func NewIsolateFromSnapshot(data []byte, opts IsolateOptions) *Isolate {
+	v8once.Do(func() {
+		C.Init()
+	})
+	var dataPtr *C.char
+	if len(data) > 0 {
+		dataPtr = (*C.char)(unsafe.Pointer(&data[0]))
+	}
+	result := C.NewIsolateFromSnapshot(dataPtr, C.int(len(data)),
+		C.ulong(opts.InitialHeapSize), C.ulong(opts.MaxHeapSize))
+	iso := &Isolate{
+		ptr:          result.isolate,
+		cbs:          make(map[int]FunctionCallback),
+		stringBuffer: make([]byte, kIsolateStringBufferSize),
+	}
+	iso.internalContext = &Context{
+		ptr: result.internalContext,
+		iso: iso,
+	}
+	iso.null = &Value{result.nullVal, iso.internalContext}
+	iso.undefined = &Value{result.undefinedVal, iso.internalContext}
+	iso.falseVal = &Value{result.falseVal, iso.internalContext}
+	iso.trueVal = &Value{result.trueVal, iso.internalContext}
+	isolatesByPtr.Store(iso.ptr, iso)
+	return iso
+}
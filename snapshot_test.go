@@ -0,0 +1,58 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8go "rogchap.com/v8go"
+)
+
+func TestCreateSnapshot(t *testing.T) {
+	t.Parallel()
+
+	snap, err := v8go.CreateSnapshot(`function greet(name) { return "hello " + name; }`, "setup.js")
+	if err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+	if len(snap.Bytes()) == 0 {
+		t.Error("expected non-empty snapshot data")
+	}
+}
+
+func TestCreateSnapshotError(t *testing.T) {
+	t.Parallel()
+
+	_, err := v8go.CreateSnapshot(`(`, "setup.js")
+	if err == nil {
+		t.Error("expected error for invalid source, got <nil>")
+	}
+	if _, ok := err.(*v8go.JSError); !ok {
+		t.Errorf("expected error to be of type JSError, got: %T", err)
+	}
+}
+
+func TestNewIsolateFromSnapshot(t *testing.T) {
+	t.Parallel()
+
+	snap, err := v8go.CreateSnapshot(`globalThis.greeting = "hi from snapshot";`, "setup.js")
+	if err != nil {
+		t.Fatalf("CreateSnapshot failed: %v", err)
+	}
+
+	iso := v8go.NewIsolateFromSnapshot(snap.Bytes(), v8go.IsolateOptions{})
+	defer iso.Dispose()
+
+	ctx := v8go.NewContext(iso)
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`greeting`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := val.String(); got != "hi from snapshot" {
+		t.Errorf("expected %q, got %q", "hi from snapshot", got)
+	}
+}
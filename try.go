@@ -0,0 +1,121 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+)
+
+// RangeError reports that a JS value is of the right kind but its actual
+// value falls outside what a Try* accessor can represent losslessly in the
+// requested Go type.
+type RangeError struct {
+	msg string
+}
+
+func (e *RangeError) Error() string { return e.msg }
+
+// TypeError reports that a JS value's type doesn't match what a Try*
+// accessor requires.
+type TypeError struct {
+	msg string
+}
+
+func (e *TypeError) Error() string { return e.msg }
+
+func newRangeError(format string, args ...interface{}) *RangeError {
+	return &RangeError{msg: fmt.Sprintf("v8go: "+format, args...)}
+}
+
+func newTypeError(format string, args ...interface{}) *TypeError {
+	return &TypeError{msg: fmt.Sprintf("v8go: "+format, args...)}
+}
+
+// TryInt64 converts this value to an int64, returning a *TypeError if it is
+// neither a Number nor a BigInt, and a *RangeError if its value can't be
+// represented losslessly (non-finite, fractional, or out of int64 range).
+// Unlike Integer, which coerces NaN to 0 and silently wraps out-of-range
+// values, TryInt64 reports those cases as errors instead.
+func (v *Value) TryInt64() (int64, error) {
+	if v.IsBigInt() {
+		b := v.BigInt()
+		if !b.IsInt64() {
+			return 0, newRangeError("BigInt %s does not fit in int64", b.String())
+		}
+		return b.Int64(), nil
+	}
+	if !v.IsNumber() {
+		return 0, newTypeError("value is not a Number or BigInt")
+	}
+	f := v.Number()
+	if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) {
+		return 0, newRangeError("number %v is not a whole, finite number", f)
+	}
+	// math.MaxInt64 rounds up to 2^63 when converted to float64, so
+	// comparing against it directly would let f == 2^63 slip through and
+	// overflow the int64(f) conversion below. Compare against the exact
+	// power-of-two bound instead.
+	if f < math.MinInt64 || f >= 0x1p63 {
+		return 0, newRangeError("number %v overflows int64", f)
+	}
+	return int64(f), nil
+}
+
+// TryUint64 is TryInt64's unsigned counterpart: it additionally rejects
+// negative values.
+func (v *Value) TryUint64() (uint64, error) {
+	if v.IsBigInt() {
+		b := v.BigInt()
+		if !b.IsUint64() {
+			return 0, newRangeError("BigInt %s does not fit in uint64", b.String())
+		}
+		return b.Uint64(), nil
+	}
+	if !v.IsNumber() {
+		return 0, newTypeError("value is not a Number or BigInt")
+	}
+	f := v.Number()
+	if math.IsNaN(f) || math.IsInf(f, 0) || f != math.Trunc(f) {
+		return 0, newRangeError("number %v is not a whole, finite number", f)
+	}
+	// As in TryInt64, math.MaxUint64 rounds up to 2^64 as a float64, so the
+	// bound has to be the exact power of two rather than the constant.
+	if f < 0 || f >= 0x1p64 {
+		return 0, newRangeError("number %v overflows uint64", f)
+	}
+	return uint64(f), nil
+}
+
+// TryFloat64 converts this value to a float64, returning a *TypeError if it
+// is not a Number. Unlike Number, which has no way to signal "not a
+// number", TryFloat64 makes a non-Number value an explicit error.
+func (v *Value) TryFloat64() (float64, error) {
+	if !v.IsNumber() {
+		return 0, newTypeError("value is not a Number")
+	}
+	return v.Number(), nil
+}
+
+// TryBool converts this value to a bool, returning a *TypeError if it is
+// not a Boolean. Unlike Boolean, which follows JS truthiness rules and
+// never fails, TryBool only succeeds for an actual JS boolean.
+func (v *Value) TryBool() (bool, error) {
+	if !v.IsBoolean() {
+		return false, newTypeError("value is not a Boolean")
+	}
+	return v.Boolean(), nil
+}
+
+// AsBigInt returns this value's BigInt, returning a *TypeError if it is not
+// one. Unlike the existing BigInt method, which coerces any Number or
+// BigInt value, AsBigInt only succeeds when IsBigInt is already true.
+func (v *Value) AsBigInt() (*big.Int, error) {
+	if !v.IsBigInt() {
+		return nil, newTypeError("value is not a BigInt")
+	}
+	return v.BigInt(), nil
+}
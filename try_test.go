@@ -0,0 +1,149 @@
+// Copyright 2021 Roger Chapman and the v8go contributors. All rights reserved.
+// Use of this source code is governed by a BSD-style license that can be
+// found in the LICENSE file.
+
+package v8go_test
+
+import (
+	"testing"
+
+	v8go "rogchap.com/v8go"
+)
+
+func TestValueTryInt64(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`42`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	n, err := val.TryInt64()
+	if err != nil {
+		t.Fatalf("TryInt64 failed: %v", err)
+	}
+	if n != 42 {
+		t.Errorf("expected 42, got %d", n)
+	}
+
+	bad, err := ctx.RunScript(`3.14`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := bad.TryInt64(); err == nil {
+		t.Error("expected a *RangeError for a fractional number, got <nil>")
+	} else if _, ok := err.(*v8go.RangeError); !ok {
+		t.Errorf("expected *RangeError, got %T", err)
+	}
+
+	notNum, err := ctx.RunScript(`"nope"`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := notNum.TryInt64(); err == nil {
+		t.Error("expected a *TypeError for a non-numeric value, got <nil>")
+	} else if _, ok := err.(*v8go.TypeError); !ok {
+		t.Errorf("expected *TypeError, got %T", err)
+	}
+}
+
+func TestValueTryInt64PowerOfTwoOverflow(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	// math.MaxInt64 rounds up to 2^63 as a float64, so 2^63 must still be
+	// rejected rather than silently accepted and wrapped by int64(f).
+	val, err := ctx.RunScript(`Math.pow(2, 63)`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, err := val.TryInt64(); err == nil {
+		t.Errorf("expected a *RangeError for 2^63, got %d", n)
+	} else if _, ok := err.(*v8go.RangeError); !ok {
+		t.Errorf("expected *RangeError, got %T", err)
+	}
+}
+
+func TestValueTryUint64PowerOfTwoOverflow(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`Math.pow(2, 64)`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if n, err := val.TryUint64(); err == nil {
+		t.Errorf("expected a *RangeError for 2^64, got %d", n)
+	} else if _, ok := err.(*v8go.RangeError); !ok {
+		t.Errorf("expected *RangeError, got %T", err)
+	}
+}
+
+func TestValueTryUint64Negative(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`-1`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := val.TryUint64(); err == nil {
+		t.Error("expected a *RangeError for a negative number, got <nil>")
+	}
+}
+
+func TestValueTryBool(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`1`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := val.TryBool(); err == nil {
+		t.Error("expected a *TypeError for a non-boolean value, got <nil>")
+	}
+}
+
+func TestValueAsBigInt(t *testing.T) {
+	t.Parallel()
+
+	ctx := v8go.NewContext()
+	defer ctx.Isolate().Dispose()
+	defer ctx.Close()
+
+	val, err := ctx.RunScript(`123n`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	b, err := val.AsBigInt()
+	if err != nil {
+		t.Fatalf("AsBigInt failed: %v", err)
+	}
+	if b.Int64() != 123 {
+		t.Errorf("expected 123, got %s", b)
+	}
+
+	notBig, err := ctx.RunScript(`123`, "main.js")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := notBig.AsBigInt(); err == nil {
+		t.Error("expected a *TypeError for a Number value, got <nil>")
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"fmt"
 	"io"
 	"math/big"
+	"reflect"
 	"strconv"
 	"unsafe"
 )
@@ -63,6 +64,15 @@ func NewValue(iso *Isolate, val interface{}) (*Value, error) {
 //
 // As a convenience, if passed a *v8.Value it returns the same Value,
 // and if passed a *v8.Object it returns the object's Value.
+//
+// Composite Go types are also supported, converted via reflection: slices
+// and arrays become JS Arrays, maps with string or integer keys and structs
+// become JS Objects (struct fields honor `json` tags for naming, `-`,
+// `omitempty`, and embedding), []byte becomes a Uint8Array, time.Time
+// becomes a JS Date, and func(...) becomes a JS Function bound to a
+// FunctionTemplate. Nested composites recurse; a Go value that contains a
+// cycle through a pointer is rejected with an error rather than recursing
+// forever.
 func (c *Context) NewValue(val interface{}) (*Value, error) {
 	ctxPtr := c.ptr
 	var ptr C.ValuePtr
@@ -105,7 +115,7 @@ func (c *Context) NewValue(val interface{}) (*Value, error) {
 	case *Object:
 		return v.Value, nil
 	default:
-		err = fmt.Errorf("v8go: unsupported value type `%T`", v)
+		return c.newValueFromReflect(reflect.ValueOf(val), make(map[uintptr]bool))
 	}
 
 	if err != nil {